@@ -0,0 +1,173 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+)
+
+// Account holder types
+const (
+	AccountHolderIndividual       = "INDIVIDUAL"
+	AccountHolderBusiness         = "BUSINESS"
+	AccountHolderSoleTrader       = "SOLE_TRADER"
+	AccountHolderJoint            = "JOINT"
+	AccountHolderBankingAsService = "BANKING_AS_A_SERVICE"
+)
+
+// AccountHolder identifies the customer that owns the accounts accessible with the token
+type AccountHolder struct {
+	UID  string `json:"accountHolderUid"`
+	Type string `json:"accountHolderType"` // INDIVIDUAL, BUSINESS, SOLE_TRADER, JOINT or BANKING_AS_A_SERVICE
+}
+
+// accountHolderName is the name of an account holder
+type accountHolderName struct {
+	Name string `json:"accountHolderName"`
+}
+
+// Individual is a person who holds an account
+type Individual struct {
+	Title       string `json:"title"`
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	DateOfBirth string `json:"dateOfBirth"` // Formatted as yyyy-mm-dd
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+}
+
+// JointAccountHolders are the two people who hold a joint account
+type JointAccountHolders struct {
+	AccountHolderUID string     `json:"accountHolderUid"`
+	PersonOne        Individual `json:"personOne"`
+	PersonTwo        Individual `json:"personTwo"`
+}
+
+// Business is a company that holds an account
+type Business struct {
+	CompanyName               string `json:"companyName"`
+	CompanyType               string `json:"companyType"`
+	CompanyCategory           string `json:"companyCategory"`
+	CompanySubCategory        string `json:"companySubCategory"`
+	CompanyRegistrationNumber string `json:"companyRegistrationNumber"`
+	Email                     string `json:"email"`
+	Phone                     string `json:"phone"`
+}
+
+// AccountHolder returns the UID and type of the account holder. It also returns the http response
+// in case this is required for further processing. An error will be returned if unable to
+// retrieve the account holder from the API.
+func (c *Client) AccountHolder(ctx context.Context) (*AccountHolder, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/account-holder", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ah *AccountHolder
+	resp, err := c.Do(ctx, req, &ah)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ah, resp, nil
+}
+
+// AccountHolderName returns the name of the account holder, whatever its type. It also returns
+// the http response in case this is required for further processing. An error will be returned
+// if unable to retrieve the name from the API.
+func (c *Client) AccountHolderName(ctx context.Context) (string, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/account-holder/name", nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var n accountHolderName
+	resp, err := c.Do(ctx, req, &n)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return n.Name, resp, nil
+}
+
+// Individual returns the details of an individual account holder. It also returns the http
+// response in case this is required for further processing. An error will be returned if unable
+// to retrieve the details from the API, including when the account holder is not an individual.
+func (c *Client) Individual(ctx context.Context) (*Individual, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/account-holder/individual", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ind *Individual
+	resp, err := c.Do(ctx, req, &ind)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ind, resp, nil
+}
+
+// JointAccountHolders returns the details of the holders of a joint account. It also returns the
+// http response in case this is required for further processing. An error will be returned if
+// unable to retrieve the details from the API, including when the account is not a joint account.
+func (c *Client) JointAccountHolders(ctx context.Context) (*JointAccountHolders, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/account-holder/joint", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var j *JointAccountHolders
+	resp, err := c.Do(ctx, req, &j)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return j, resp, nil
+}
+
+// Business returns the details of a business account holder. It also returns the http response in
+// case this is required for further processing. An error will be returned if unable to retrieve
+// the details from the API, including when the account holder is not a business.
+func (c *Client) Business(ctx context.Context) (*Business, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/account-holder/business", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b *Business
+	resp, err := c.Do(ctx, req, &b)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return b, resp, nil
+}
+
+// BusinessRegisteredAddress returns the registered address of a business account holder. It also
+// returns the http response in case this is required for further processing. An error will be
+// returned if unable to retrieve the address from the API.
+func (c *Client) BusinessRegisteredAddress(ctx context.Context) (*Address, *http.Response, error) {
+	return c.businessAddress(ctx, "registered-address")
+}
+
+// BusinessCorrespondenceAddress returns the correspondence address of a business account holder.
+// It also returns the http response in case this is required for further processing. An error
+// will be returned if unable to retrieve the address from the API.
+func (c *Client) BusinessCorrespondenceAddress(ctx context.Context) (*Address, *http.Response, error) {
+	return c.businessAddress(ctx, "correspondence-address")
+}
+
+func (c *Client) businessAddress(ctx context.Context, kind string) (*Address, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/account-holder/business/"+kind, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var addr *Address
+	resp, err := c.Do(ctx, req, &addr)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return addr, resp, nil
+}
@@ -0,0 +1,137 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAccountHolder(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/account-holder", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"accountHolderUid":"6f2b0c4a-1d3e-4f5a-8b6c-7d8e9f0a1b2c","accountHolderType":"SOLE_TRADER"}`)
+	})
+
+	got, _, err := client.AccountHolder(context.Background())
+	checkNoError(t, err)
+
+	want := &AccountHolder{UID: "6f2b0c4a-1d3e-4f5a-8b6c-7d8e9f0a1b2c", Type: AccountHolderSoleTrader}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("should return the account holder", cross, got)
+	}
+}
+
+func TestAccountHolderName(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/account-holder/name", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"accountHolderName":"Widgets Limited"}`)
+	})
+
+	got, _, err := client.AccountHolderName(context.Background())
+	checkNoError(t, err)
+
+	if got != "Widgets Limited" {
+		t.Error("should return the account holder name", cross, got)
+	}
+}
+
+func TestIndividual(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/account-holder/individual", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"title":"Ms","firstName":"Jane","lastName":"Smith","dateOfBirth":"1985-03-14","email":"jane@example.com","phone":"+447700900123"}`)
+	})
+
+	got, _, err := client.Individual(context.Background())
+	checkNoError(t, err)
+
+	want := &Individual{Title: "Ms", FirstName: "Jane", LastName: "Smith", DateOfBirth: "1985-03-14", Email: "jane@example.com", Phone: "+447700900123"}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("should return the individual", cross, got)
+	}
+}
+
+func TestIndividualNotIndividual(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/account-holder/individual", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	got, _, err := client.Individual(context.Background())
+	checkHasError(t, err)
+
+	if got != nil {
+		t.Error("should not return an individual", cross, got)
+	}
+}
+
+func TestJointAccountHolders(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/account-holder/joint", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"accountHolderUid": "6f2b0c4a-1d3e-4f5a-8b6c-7d8e9f0a1b2c",
+			"personOne": {"firstName": "Jane", "lastName": "Smith"},
+			"personTwo": {"firstName": "John", "lastName": "Smith"}
+		}`)
+	})
+
+	got, _, err := client.JointAccountHolders(context.Background())
+	checkNoError(t, err)
+
+	if got == nil || got.PersonOne.FirstName != "Jane" || got.PersonTwo.FirstName != "John" {
+		t.Error("should return both account holders", cross, got)
+	}
+}
+
+func TestBusiness(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/account-holder/business", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"companyName":"Widgets Limited","companyType":"LIMITED","companyRegistrationNumber":"01234567"}`)
+	})
+	mux.HandleFunc("/api/v2/account-holder/business/registered-address", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"line1":"1 Registered Way","postTown":"London","postCode":"EC1A 1AA","countryCode":"GB"}`)
+	})
+	mux.HandleFunc("/api/v2/account-holder/business/correspondence-address", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"line1":"PO Box 42","postTown":"Leeds","postCode":"LS1 1AA","countryCode":"GB"}`)
+	})
+
+	ctx := context.Background()
+
+	b, _, err := client.Business(ctx)
+	checkNoError(t, err)
+	if b == nil || b.CompanyName != "Widgets Limited" || b.CompanyRegistrationNumber != "01234567" {
+		t.Error("should return the business", cross, b)
+	}
+
+	reg, _, err := client.BusinessRegisteredAddress(ctx)
+	checkNoError(t, err)
+	if reg == nil || reg.Line1 != "1 Registered Way" {
+		t.Error("should return the registered address", cross, reg)
+	}
+
+	cor, _, err := client.BusinessCorrespondenceAddress(ctx)
+	checkNoError(t, err)
+	if cor == nil || cor.Line1 != "PO Box 42" {
+		t.Error("should return the correspondence address", cross, cor)
+	}
+}
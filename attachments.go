@@ -0,0 +1,93 @@
+package starling
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Attachment types
+const (
+	AttachmentTypeImage = "IMAGE"
+	AttachmentTypePDF   = "PDF"
+)
+
+// Attachment is a file attached to a feed item, such as a photo of a receipt
+type Attachment struct {
+	FeedItemUID   string `json:"feedItemUid"`
+	UID           string `json:"feedItemAttachmentUid"`
+	Type          string `json:"feedItemAttachmentType"` // IMAGE or PDF
+	ContentLength int64  `json:"attachmentContentLength"`
+}
+
+// attachments is a list of the attachments on a feed item
+type attachments struct {
+	Attachments []Attachment `json:"feedItemAttachments"`
+}
+
+// attachmentUploadResponse represents the response after uploading an attachment
+type attachmentUploadResponse struct {
+	UID string `json:"feedItemAttachmentUid"`
+}
+
+// attachmentsPath returns the path of the attachments on a feed item.
+func attachmentsPath(act, cat, itm string) string {
+	return "/api/v2/feed/account/" + act + "/category/" + cat + "/" + itm + "/attachments"
+}
+
+// Attachments returns the attachments on a feed item. It also returns the http response in case
+// this is required for further processing. An error will be returned if unable to retrieve the
+// attachments from the API.
+func (c *Client) Attachments(ctx context.Context, act, cat, itm string) ([]Attachment, *http.Response, error) {
+	req, err := c.NewRequest("GET", attachmentsPath(act, cat, itm), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var a attachments
+	resp, err := c.Do(ctx, req, &a)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return a.Attachments, resp, nil
+}
+
+// DownloadAttachment writes the content of an attachment to w. The content is streamed rather
+// than held in memory. It returns the content type of the attachment, such as image/jpeg or
+// application/pdf, and the http response in case this is required for further processing. An
+// error is returned on failure, in which case part of the attachment may already have been
+// written to w.
+func (c *Client) DownloadAttachment(ctx context.Context, act, cat, itm, uid string, w io.Writer) (string, *http.Response, error) {
+	req, err := c.NewRequest("GET", attachmentsPath(act, cat, itm)+"/"+uid, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := c.stream(ctx, req, w)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return resp.Header.Get("Content-Type"), resp, nil
+}
+
+// UploadAttachment attaches an image or PDF to a feed item. The contentType should be image/jpeg,
+// image/png or application/pdf. The upload is not retried, as that could attach the file twice.
+// It returns the UID of the new attachment and the http response in case this is required for
+// further processing. An error will be returned if the API is unable to store the attachment.
+func (c *Client) UploadAttachment(ctx context.Context, act, cat, itm, contentType string, r io.Reader) (string, *http.Response, error) {
+	req, err := c.newUploadRequest("POST", attachmentsPath(act, cat, itm), contentType, r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var aResp attachmentUploadResponse
+	resp, err := c.Do(ctx, req, &aResp)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return aResp.UID, resp, nil
+}
@@ -0,0 +1,91 @@
+package starling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const attachmentsTestPath = "/api/v2/feed/account/30aa7ab8-4389-4658-a4f8-0bc6d0015ba0/category/c423ab8d-9a6a-44b2-8db6-ac6000fe58e0/dbb59f1c-39e6-4558-87ba-11c142965393/attachments"
+
+func TestAttachments(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(attachmentsTestPath, func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"feedItemAttachments": [
+				{
+					"feedItemUid": "dbb59f1c-39e6-4558-87ba-11c142965393",
+					"feedItemAttachmentUid": "f0e1d2c3-b4a5-4968-8776-5a4b3c2d1e0f",
+					"feedItemAttachmentType": "IMAGE",
+					"attachmentContentLength": 48213
+				}
+			]
+		}`)
+	})
+
+	got, _, err := client.Attachments(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", "dbb59f1c-39e6-4558-87ba-11c142965393")
+	checkNoError(t, err)
+
+	want := []Attachment{{
+		FeedItemUID:   "dbb59f1c-39e6-4558-87ba-11c142965393",
+		UID:           "f0e1d2c3-b4a5-4968-8776-5a4b3c2d1e0f",
+		Type:          AttachmentTypeImage,
+		ContentLength: 48213,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("should return the attachments", cross, got)
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(attachmentsTestPath+"/f0e1d2c3-b4a5-4968-8776-5a4b3c2d1e0f", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG\r\n"))
+	})
+
+	var buf bytes.Buffer
+	ct, _, err := client.DownloadAttachment(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", "dbb59f1c-39e6-4558-87ba-11c142965393", "f0e1d2c3-b4a5-4968-8776-5a4b3c2d1e0f", &buf)
+	checkNoError(t, err)
+
+	if ct != "image/png" {
+		t.Error("should return the content type of the attachment", cross, ct)
+	}
+	if buf.String() != "\x89PNG\r\n" {
+		t.Error("should write the attachment to the writer", cross)
+	}
+}
+
+func TestUploadAttachment(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(attachmentsTestPath, func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPost)
+		if ct := r.Header.Get("Content-Type"); ct != "application/pdf" {
+			t.Error("should send the content type of the attachment", cross, ct)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "%PDF-1.4" {
+			t.Error("should send the attachment unencoded", cross, string(body))
+		}
+		fmt.Fprint(w, `{"feedItemAttachmentUid":"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"}`)
+	})
+
+	uid, _, err := client.UploadAttachment(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", "dbb59f1c-39e6-4558-87ba-11c142965393", "application/pdf", bytes.NewReader([]byte("%PDF-1.4")))
+	checkNoError(t, err)
+
+	if uid != "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d" {
+		t.Error("should return the UID of the attachment", cross, uid)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Package auth implements the Starling OAuth authorisation-code flow and keeps
+the resulting tokens fresh and persisted.
+
+Send the customer to the URL returned by AuthCodeURL, exchange the code they
+return with for a token, save it, then build a client from the store:
+
+	conf := &auth.Config{ClientID: id, ClientSecret: secret, RedirectURL: redirect}
+	store := auth.NewFileStore("token.json")
+
+	tok, err := conf.Exchange(ctx, code)
+	if err != nil {
+		return err
+	}
+	if err := store.SaveToken(tok); err != nil {
+		return err
+	}
+
+	client := starling.NewClient(conf.Client(ctx, store))
+
+Tokens are refreshed shortly before they expire and the refreshed token is
+written back to the store so that restarts resume with a valid token.
+*/
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ProdEndpoint is the OAuth endpoint for the production instance of the Starling API
+	ProdEndpoint = oauth2.Endpoint{
+		AuthURL:   "https://oauth.starlingbank.com/",
+		TokenURL:  "https://api.starlingbank.com/oauth/access-token",
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+
+	// SandboxEndpoint is the OAuth endpoint for the sandbox instance of the Starling API
+	SandboxEndpoint = oauth2.Endpoint{
+		AuthURL:   "https://oauth-sandbox.starlingbank.com/",
+		TokenURL:  "https://api-sandbox.starlingbank.com/oauth/access-token",
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+)
+
+// defaultRefreshBefore is how long before expiry a token is refreshed if the
+// Config does not say otherwise.
+const defaultRefreshBefore = time.Minute
+
+// Config describes a Starling OAuth client application.
+type Config struct {
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Endpoint      oauth2.Endpoint // Defaults to SandboxEndpoint, matching the default starling.Client
+	RefreshBefore time.Duration   // How long before expiry tokens are refreshed; defaults to one minute
+}
+
+func (c *Config) oauth2() *oauth2.Config {
+	ep := c.Endpoint
+	if ep.AuthURL == "" && ep.TokenURL == "" {
+		ep = SandboxEndpoint
+	}
+	return &oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RedirectURL:  c.RedirectURL,
+		Endpoint:     ep,
+	}
+}
+
+// NewState returns a random value suitable for the state parameter of AuthCodeURL.
+// It should be stored with the customer session and compared with the state
+// returned to the redirect URL.
+func NewState() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "unable to generate state")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeURL returns the URL of the Starling page that asks the customer to
+// authorise the application.
+func (c *Config) AuthCodeURL(state string) string {
+	return c.oauth2().AuthCodeURL(state)
+}
+
+// Exchange converts an authorisation code into a token. The token is not
+// persisted; save it to a TokenStore before calling TokenSource or Client.
+func (c *Config) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	tok, err := c.oauth2().Exchange(ctx, code)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to exchange authorisation code")
+	}
+	return tok, nil
+}
+
+// TokenSource returns an oauth2.TokenSource that reads the token from store,
+// refreshes it before it expires and saves the refreshed token back to store.
+// The context is used when refreshing tokens.
+func (c *Config) TokenSource(ctx context.Context, store TokenStore) oauth2.TokenSource {
+	early := c.RefreshBefore
+	if early <= 0 {
+		early = defaultRefreshBefore
+	}
+	return &storeTokenSource{ctx: ctx, conf: c.oauth2(), store: store, early: early}
+}
+
+// Client returns an HTTP client that authenticates requests using the token held
+// in store. It can be passed directly to starling.NewClient. If the context holds
+// an *http.Client under oauth2.HTTPClient, its transport is used to send requests.
+func (c *Config) Client(ctx context.Context, store TokenStore) *http.Client {
+	var base http.RoundTripper
+	if hc, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		base = hc.Transport
+	}
+	return &http.Client{
+		Transport: &oauth2.Transport{Source: c.TokenSource(ctx, store), Base: base},
+	}
+}
+
+// storeTokenSource is an oauth2.TokenSource backed by a TokenStore.
+type storeTokenSource struct {
+	ctx   context.Context
+	conf  *oauth2.Config
+	store TokenStore
+	early time.Duration
+
+	mu  sync.Mutex
+	tok *oauth2.Token
+}
+
+// Token returns a token that will remain valid for at least the refresh window,
+// refreshing and persisting it if necessary.
+func (s *storeTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fresh(s.tok) {
+		return s.tok, nil
+	}
+
+	// Another process sharing the store may already have refreshed the token.
+	tok, err := s.store.LoadToken()
+	if err != nil {
+		return nil, err
+	}
+	if s.fresh(tok) {
+		s.tok = tok
+		return tok, nil
+	}
+
+	if tok.RefreshToken == "" {
+		return nil, errors.New("token has expired and has no refresh token")
+	}
+
+	refreshed, err := s.conf.TokenSource(s.ctx, &oauth2.Token{RefreshToken: tok.RefreshToken}).Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to refresh token")
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = tok.RefreshToken
+	}
+
+	if err := s.store.SaveToken(refreshed); err != nil {
+		return nil, err
+	}
+	s.tok = refreshed
+	return refreshed, nil
+}
+
+// fresh reports whether the token will remain valid for the refresh window.
+func (s *storeTokenSource) fresh(tok *oauth2.Token) bool {
+	if tok == nil || tok.AccessToken == "" {
+		return false
+	}
+	if tok.Expiry.IsZero() {
+		return true
+	}
+	return time.Now().Add(s.early).Before(tok.Expiry)
+}
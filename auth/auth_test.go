@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const cross = "✗"
+
+// setup returns a Config whose endpoints point at a test server handling the
+// token endpoint with h.
+func setup(h http.HandlerFunc) (*Config, func()) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/access-token", h)
+	server := httptest.NewServer(mux)
+
+	conf := &Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://example.com/callback",
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   server.URL + "/",
+			TokenURL:  server.URL + "/oauth/access-token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+	return conf, server.Close
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	conf := &Config{ClientID: "client-id", RedirectURL: "https://example.com/callback"}
+
+	u, err := url.Parse(conf.AuthCodeURL("xyz"))
+	if err != nil {
+		t.Fatal("should return a valid URL", cross, err)
+	}
+
+	if got, want := u.Scheme+"://"+u.Host+u.Path, SandboxEndpoint.AuthURL; got != want {
+		t.Error("should default to the sandbox endpoint", cross, got)
+	}
+
+	q := u.Query()
+	for k, want := range map[string]string{
+		"client_id":     "client-id",
+		"response_type": "code",
+		"state":         "xyz",
+		"redirect_uri":  "https://example.com/callback",
+	} {
+		if got := q.Get(k); got != want {
+			t.Errorf("should set %s %s %s", k, cross, got)
+		}
+	}
+}
+
+func TestNewState(t *testing.T) {
+	a, err := NewState()
+	if err != nil {
+		t.Fatal("should generate state without error", cross, err)
+	}
+	b, _ := NewState()
+	if a == "" || a == b {
+		t.Error("should generate unique state values", cross, a, b)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	conf, teardown := setup(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		for k, want := range map[string]string{
+			"grant_type":    "authorization_code",
+			"code":          "auth-code",
+			"client_id":     "client-id",
+			"client_secret": "client-secret",
+			"redirect_uri":  "https://example.com/callback",
+		} {
+			if got := r.PostForm.Get(k); got != want {
+				t.Errorf("should send %s %s %s", k, cross, got)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":86400}`)
+	})
+	defer teardown()
+
+	tok, err := conf.Exchange(context.Background(), "auth-code")
+	if err != nil {
+		t.Fatal("should exchange the code without error", cross, err)
+	}
+	if tok.AccessToken != "access" || tok.RefreshToken != "refresh" {
+		t.Error("should return the issued token", cross, tok)
+	}
+	if tok.Expiry.Before(time.Now().Add(23 * time.Hour)) {
+		t.Error("should set the token expiry", cross, tok.Expiry)
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	t.Run("uses a fresh stored token", func(tc *testing.T) {
+		conf, teardown := setup(func(w http.ResponseWriter, r *http.Request) {
+			tc.Error("should not refresh a fresh token", cross)
+		})
+		defer teardown()
+
+		store := &MemoryStore{}
+		store.SaveToken(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)})
+
+		tok, err := conf.TokenSource(context.Background(), store).Token()
+		if err != nil || tok.AccessToken != "access" {
+			tc.Error("should return the stored token", cross, tok, err)
+		}
+	})
+
+	t.Run("refreshes a token that is about to expire", func(tc *testing.T) {
+		calls := 0
+		conf, teardown := setup(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			r.ParseForm()
+			if got := r.PostForm.Get("grant_type"); got != "refresh_token" {
+				tc.Error("should request a refresh", cross, got)
+			}
+			if got := r.PostForm.Get("refresh_token"); got != "refresh" {
+				tc.Error("should send the stored refresh token", cross, got)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","token_type":"Bearer","expires_in":86400}`)
+		})
+		defer teardown()
+		conf.RefreshBefore = 5 * time.Minute
+
+		path := filepath.Join(tc.TempDir(), "token.json")
+		store := NewFileStore(path)
+		store.SaveToken(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Minute)})
+
+		ts := conf.TokenSource(context.Background(), store)
+		tok, err := ts.Token()
+		if err != nil || tok.AccessToken != "new-access" {
+			tc.Fatal("should return the refreshed token", cross, tok, err)
+		}
+
+		tok, _ = ts.Token()
+		if calls != 1 || tok.AccessToken != "new-access" {
+			tc.Error("should reuse the refreshed token", cross, calls)
+		}
+
+		saved, err := NewFileStore(path).LoadToken()
+		if err != nil || saved.AccessToken != "new-access" || saved.RefreshToken != "new-refresh" {
+			tc.Error("should persist the refreshed token", cross, saved, err)
+		}
+	})
+
+	t.Run("returns an error when no token is stored", func(tc *testing.T) {
+		conf, teardown := setup(func(w http.ResponseWriter, r *http.Request) {})
+		defer teardown()
+
+		_, err := conf.TokenSource(context.Background(), &MemoryStore{}).Token()
+		if err != ErrNoToken {
+			tc.Error("should return ErrNoToken", cross, err)
+		}
+	})
+}
+
+func TestClient(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer access"; got != want {
+			t.Error("should authenticate requests with the stored token", cross, got)
+		}
+	}))
+	defer api.Close()
+
+	store := &MemoryStore{}
+	store.SaveToken(&oauth2.Token{AccessToken: "access", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+
+	conf := &Config{}
+	resp, err := conf.Client(context.Background(), store).Get(api.URL)
+	if err != nil {
+		t.Fatal("should send the request without error", cross, err)
+	}
+	resp.Body.Close()
+}
+
+func TestFileStore(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "token.json"))
+
+	if _, err := store.LoadToken(); err != ErrNoToken {
+		t.Error("should return ErrNoToken before a token is saved", cross, err)
+	}
+
+	want := &oauth2.Token{AccessToken: "a", RefreshToken: "r", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour).Round(time.Second)}
+	if err := store.SaveToken(want); err != nil {
+		t.Fatal("should save the token", cross, err)
+	}
+
+	got, err := store.LoadToken()
+	if err != nil {
+		t.Fatal("should load the token", cross, err)
+	}
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || !got.Expiry.Equal(want.Expiry) {
+		t.Error("should load the saved token", cross, got)
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/astravexton/starling/internal/atomicfile"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// ErrNoToken is returned by a TokenStore that does not hold a token.
+var ErrNoToken = errors.New("no token stored")
+
+// TokenStore persists OAuth tokens between runs. Implementations must be safe
+// for concurrent use.
+type TokenStore interface {
+	LoadToken() (*oauth2.Token, error) // Returns ErrNoToken if no token has been saved
+	SaveToken(*oauth2.Token) error
+}
+
+// FileStore is a TokenStore that keeps the token as JSON in a file readable only
+// by the current user.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore that reads and writes the token at path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// LoadToken reads the token from the file.
+func (s *FileStore) LoadToken() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read token")
+	}
+
+	var tok oauth2.Token
+	if err := json.Unmarshal(b, &tok); err != nil {
+		return nil, errors.Wrap(err, "unable to parse token")
+	}
+	return &tok, nil
+}
+
+// SaveToken writes the token to the file.
+func (s *FileStore) SaveToken(tok *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := json.Marshal(tok)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode token")
+	}
+	return errors.Wrap(atomicfile.WriteFile(s.path, b), "unable to save token")
+}
+
+// MemoryStore is a TokenStore that holds the token in memory.
+type MemoryStore struct {
+	mu  sync.Mutex
+	tok *oauth2.Token
+}
+
+// LoadToken returns the saved token.
+func (s *MemoryStore) LoadToken() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tok == nil {
+		return nil, ErrNoToken
+	}
+	tok := *s.tok
+	return &tok, nil
+}
+
+// SaveToken replaces the saved token.
+func (s *MemoryStore) SaveToken(tok *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := *tok
+	s.tok = &t
+	return nil
+}
@@ -30,6 +30,7 @@ const (
 // ClientOptions is a set of options that can be specified when creating a Starling client
 type ClientOptions struct {
 	BaseURL *url.URL
+	Retry   *RetryPolicy // Retry policy for transient failures; nil disables retries
 }
 
 // Client holds configuration items for the Starling client and provides methods
@@ -39,6 +40,7 @@ type Client struct {
 
 	userAgent string
 	client    *http.Client
+	retry     *RetryPolicy
 }
 
 // NewClient returns a new Starling API client. If a nil httpClient is
@@ -60,6 +62,7 @@ func NewClient(cc *http.Client) *Client {
 func NewClientWithOptions(cc *http.Client, opts ClientOptions) *Client {
 	c := NewClient(cc)
 	c.baseURL = opts.BaseURL
+	c.retry = opts.Retry
 	return c
 }
 
@@ -103,66 +106,127 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	return req, nil
 }
 
+// newUploadRequest creates an HTTP Request in the same way as NewRequest but with a raw body of
+// the given content type rather than a JSON encoded one.
+func (c *Client) newUploadRequest(method, urlStr, contentType string, body io.Reader) (*http.Request, error) {
+	req, err := c.NewRequest(method, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := http.NewRequest(method, req.URL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	up.Header = req.Header
+	up.Header.Set("Content-Type", contentType)
+	return up, nil
+}
+
 // Do sends a request and returns the response. An error is returned if the request cannot
 // be sent or if the API returns an error. If a response is received, the body response body
-// is decoded and stored in the value pointed to by v.
+// is decoded and stored in the value pointed to by v. If the client has a RetryPolicy,
+// transient failures are retried and the error returned after more than one attempt is a
+// *RetryError.
 // Inspiration: https://github.com/google/go-github/blob/master/github/github.go
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
-	req = req.WithContext(ctx)
-	resp, err := c.client.Do(req)
+	resp, data, attempts, err := c.send(ctx, req, false)
+	if err == nil {
+		err = decodeResponse(resp, data, v)
+	}
 
-	if err != nil {
-		select {
-		case <-ctx.Done():
-			return nil, errors.Wrap(err, ctx.Err().Error())
-		default:
-			return nil, err
+	if err != nil && attempts > 1 {
+		err = &RetryError{Attempts: attempts, Err: err}
+	}
+	return resp, err
+}
+
+// stream sends a request and copies a successful response body to w rather than buffering it
+// in memory. Error responses are returned as an *APIError, as they are by Do. Retries are only
+// made before any of the body has been written.
+func (c *Client) stream(ctx context.Context, req *http.Request, w io.Writer) (*http.Response, error) {
+	resp, data, attempts, err := c.send(ctx, req, true)
+	if err == nil {
+		if resp.StatusCode >= 300 {
+			err = newAPIError(resp, data)
+		} else {
+			_, err = io.Copy(w, resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				err = errors.Wrap(err, "unable to read body")
+			}
 		}
 	}
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to read body")
+	if err != nil && attempts > 1 {
+		err = &RetryError{Attempts: attempts, Err: err}
 	}
-	resp.Body.Close()
+	return resp, err
+}
 
-	// Anything other than a HTTP 2xx response code is treated as an error. But the structure of error
-	// responses differs depending on the API being called. Some APIs return validation errors as part
-	// of the standard response. Others respond with a standardised error structure.
-	if c := resp.StatusCode; c >= 300 {
+// send performs the request, retrying transient failures according to the client
+// RetryPolicy. It returns the final response along with its body, which has been
+// read and closed, and the number of attempts made. If stream is true, the body of
+// a successful response is left open for the caller to read and close instead.
+func (c *Client) send(ctx context.Context, req *http.Request, stream bool) (*http.Response, []byte, int, error) {
+	maxAttempts := 1
+	if c.retry != nil && c.retry.MaxAttempts > 1 && canRetry(req) {
+		maxAttempts = c.retry.MaxAttempts
+	}
 
-		// Handle auth errors
-		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-			err := AuthError(http.StatusText(resp.StatusCode))
-			return resp, err
+	for attempt := 1; ; attempt++ {
+		r := req.WithContext(ctx)
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, nil, attempt - 1, errors.Wrap(err, "unable to reset body")
+			}
+			r.Body = body
 		}
 
-		// Try parsing the response using the standard error schema. If this fails we wrap the parsing
-		// error and return. Otherwise return the errors included in the API response payload.
-		var e = Errors{}
-		err := json.Unmarshal(data, &e)
+		resp, err := c.client.Do(r)
 		if err != nil {
-			err = errors.Wrap(err, http.StatusText(resp.StatusCode))
-			return resp, errors.Wrap(err, "unable to parse API error response")
+			select {
+			case <-ctx.Done():
+				return nil, nil, attempt, errors.Wrap(err, ctx.Err().Error())
+			default:
+			}
+			if attempt < maxAttempts && sleep(ctx, c.retry.delay(attempt, nil)) {
+				continue
+			}
+			return nil, nil, attempt, err
 		}
 
-		if len(e) != 0 {
-			return resp, errors.Wrap(e, http.StatusText(resp.StatusCode))
+		if stream && resp.StatusCode < 300 {
+			return resp, nil, attempt, nil
 		}
 
-		// In some cases, the error response is returned as part of the
-		// requested resource. In these cases we attempt to decode the
-		// resource and return the error.
-		err = json.Unmarshal(data, v)
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
-			err = errors.Wrap(err, http.StatusText(resp.StatusCode))
-			return resp, errors.Wrap(err, "unable to parse API response")
+			return resp, nil, attempt, errors.Wrap(err, "unable to read body")
+		}
+
+		if retryableStatus(resp.StatusCode) && attempt < maxAttempts && sleep(ctx, c.retry.delay(attempt, resp)) {
+			continue
 		}
+		return resp, data, attempt, nil
+	}
+}
 
-		err = errors.New("no additional error information available")
-		return resp, errors.Wrap(err, http.StatusText(resp.StatusCode))
+// decodeResponse checks the response status and decodes the body into v. Any HTTP status
+// code other than 2xx is returned as an *APIError.
+func decodeResponse(resp *http.Response, data []byte, v interface{}) error {
+	if resp.StatusCode >= 300 {
+		// In some cases, the error response is returned as part of the requested
+		// resource, so we attempt to decode it for the caller to inspect.
+		if v != nil {
+			_ = json.Unmarshal(data, v)
+		}
+		return newAPIError(resp, data)
 	}
 
+	var err error
 	if v != nil && len(data) != 0 {
 		err = json.Unmarshal(data, v)
 
@@ -175,5 +239,49 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 		}
 	}
 
-	return resp, err
+	return err
+}
+
+// newAPIError builds an *APIError from an error response. The structure of error responses
+// differs depending on the API being called: some return a list of strings, others a list of
+// structured errors or an OAuth style error.
+func newAPIError(resp *http.Response, data []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		Body:       data,
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		e.Err = AuthError(http.StatusText(resp.StatusCode))
+	}
+
+	var msgs Errors
+	if err := json.Unmarshal(data, &msgs); err == nil {
+		e.Errors = msgs
+		return e
+	}
+
+	var body struct {
+		Errors      []ErrorDetail `json:"errors"`
+		Error       string        `json:"error"`
+		Description string        `json:"error_description"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil {
+		e.Details = body.Errors
+		for _, d := range body.Errors {
+			e.Errors = append(e.Errors, d.Message)
+		}
+		if body.Error != "" {
+			e.Errors = append(e.Errors, body.Error)
+		}
+		if body.Description != "" {
+			e.Errors = append(e.Errors, body.Description)
+		}
+	}
+	return e
 }
@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -241,7 +243,8 @@ func TestDo(t *testing.T) {
 
 		checkStatus(tc, resp, http.StatusForbidden)
 		checkHasError(tc, err)
-		if _, ok := err.(AuthError); ok == false {
+		var authErr AuthError
+		if !errors.As(err, &authErr) {
 			t.Errorf("should return a starling.AuthError: %T", err)
 		}
 		if err, ok := err.(Error); ok == true && err.Temporary() == true {
@@ -10,7 +10,7 @@ different functions of the Starling API. For example:
 
 	client := starling.NewClient(nil)
 
-	// retrieve transactions for the current user
+	// retrieve transactions across all accounts of the current user
 	txns, _, err := client.Transactions(ctx, nil)
 
 The majority of the API calls will require you to pass in an access token:
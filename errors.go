@@ -1,5 +1,12 @@
 package starling
 
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 // Error specifies additional methods on the standard error interface
 type Error interface {
 	error
@@ -13,3 +20,69 @@ func (e AuthError) Error() string { return string(e) }
 
 // Temporary indicates if an error is temporary
 func (e AuthError) Temporary() bool { return false }
+
+// Sentinel errors that an *APIError can be matched against using errors.Is.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrValidation        = errors.New("validation failed")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+)
+
+// APIError is returned when the API responds with anything other than a HTTP 2xx
+// status code. It holds the status, the errors parsed from the response body and
+// enough detail about the request to trace it with Starling. Authentication
+// failures wrap an AuthError.
+type APIError struct {
+	StatusCode int           // HTTP status code of the response
+	Method     string        // HTTP method of the request
+	URL        string        // URL of the request
+	RequestID  string        // Value of the X-Request-Id response header, if present
+	Errors     Errors        // Error messages parsed from the response body
+	Details    []ErrorDetail // Structured errors parsed from the response body
+	Body       []byte        // Raw response body
+	Err        error         // Underlying error, if any
+}
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	msg := "no additional error information available"
+	if len(e.Errors) != 0 {
+		msg = e.Errors.Error()
+	}
+	return http.StatusText(e.StatusCode) + ": " + msg
+}
+
+// Unwrap returns the underlying error.
+func (e *APIError) Unwrap() error { return e.Err }
+
+// Temporary indicates if the request may succeed if retried.
+func (e *APIError) Temporary() bool { return retryableStatus(e.StatusCode) }
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrInsufficientFunds:
+		for _, m := range e.Errors {
+			if strings.Contains(strings.ToUpper(m), "INSUFFICIENT_FUNDS") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err indicates that the requested resource does not exist.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsValidation reports whether err indicates that the API rejected the request as invalid.
+func IsValidation(err error) bool { return errors.Is(err, ErrValidation) }
+
+// IsInsufficientFunds reports whether err indicates that the account has insufficient funds.
+func IsInsufficientFunds(err error) bool { return errors.Is(err, ErrInsufficientFunds) }
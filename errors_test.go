@@ -0,0 +1,136 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+var apiErrorCases = []struct {
+	name       string
+	status     int
+	mock       string
+	messages   Errors
+	notFound   bool
+	validation bool
+	funds      bool
+	auth       bool
+	temporary  bool
+}{
+	{
+		name:     "not found",
+		status:   http.StatusNotFound,
+		notFound: true,
+	},
+	{
+		name:       "list of errors",
+		status:     http.StatusBadRequest,
+		mock:       `["UNKNOWN_CATEGORY"]`,
+		messages:   Errors{"UNKNOWN_CATEGORY"},
+		validation: true,
+	},
+	{
+		name:       "structured errors",
+		status:     http.StatusBadRequest,
+		mock:       `{"errors":[{"message":"INSUFFICIENT_FUNDS"}],"success":false}`,
+		messages:   Errors{"INSUFFICIENT_FUNDS"},
+		validation: true,
+		funds:      true,
+	},
+	{
+		name:     "oauth error",
+		status:   http.StatusForbidden,
+		mock:     `{"error":"invalid_token","error_description":"Could not validate provided access token"}`,
+		messages: Errors{"invalid_token", "Could not validate provided access token"},
+		auth:     true,
+	},
+	{
+		name:      "server error",
+		status:    http.StatusServiceUnavailable,
+		mock:      `<html></html>`,
+		temporary: true,
+	},
+}
+
+func TestAPIError(t *testing.T) {
+	for _, c := range apiErrorCases {
+		t.Run(c.name, func(tc *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "b1f9c2a4")
+				w.WriteHeader(c.status)
+				fmt.Fprint(w, c.mock)
+			})
+
+			req, _ := client.NewRequest("GET", "api/v2/thing", nil)
+			_, err := client.Do(context.Background(), req, nil)
+			checkHasError(tc, err)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				tc.Fatalf("should return an *APIError %s %T", cross, err)
+			}
+
+			if apiErr.StatusCode != c.status {
+				tc.Error("should record the status code", cross, apiErr.StatusCode)
+			}
+			if apiErr.Method != http.MethodGet || apiErr.URL != client.baseURL.String()+"api/v2/thing" {
+				tc.Error("should record the request", cross, apiErr.Method, apiErr.URL)
+			}
+			if apiErr.RequestID != "b1f9c2a4" {
+				tc.Error("should record the request identifier", cross, apiErr.RequestID)
+			}
+			if string(apiErr.Body) != c.mock {
+				tc.Error("should record the raw body", cross, string(apiErr.Body))
+			}
+			if fmt.Sprint(apiErr.Errors) != fmt.Sprint(c.messages) {
+				tc.Error("should parse the error messages", cross, apiErr.Errors)
+			}
+
+			if IsNotFound(err) != c.notFound {
+				tc.Error("should match ErrNotFound", cross, c.notFound)
+			}
+			if IsValidation(err) != c.validation {
+				tc.Error("should match ErrValidation", cross, c.validation)
+			}
+			if IsInsufficientFunds(err) != c.funds {
+				tc.Error("should match ErrInsufficientFunds", cross, c.funds)
+			}
+
+			var authErr AuthError
+			if errors.As(err, &authErr) != c.auth {
+				tc.Error("should wrap an AuthError", cross, c.auth)
+			}
+
+			if e, ok := err.(Error); !ok || e.Temporary() != c.temporary {
+				tc.Error("should report whether the error is temporary", cross, c.temporary)
+			}
+		})
+	}
+}
+
+func TestAPIErrorAfterRetry(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retry = testRetryPolicy()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequest("GET", ".", nil)
+	_, err := client.Do(context.Background(), req, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("should wrap the final *APIError %s %v", cross, err)
+	}
+	if e, ok := err.(Error); !ok || !e.Temporary() {
+		t.Error("should report a temporary error", cross)
+	}
+}
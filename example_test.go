@@ -82,3 +82,27 @@ func Example_balance() {
 	}
 	fmt.Printf("%v", bal)
 }
+
+func Example_transactions() {
+	godotenv.Load(".env")
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: os.Getenv("STARLING_DEV_TOKEN")})
+	ctx := context.Background()
+	tc := oauth2.NewClient(ctx, ts)
+
+	baseURL, _ := url.Parse(starling.ProdURL)
+	opts := starling.ClientOptions{BaseURL: baseURL}
+	client := starling.NewClientWithOptions(tc, opts)
+
+	// Outgoing payments over the last month
+	txns, _, err := client.Transactions(ctx, &starling.TransactionOptions{
+		Direction: "OUT",
+		Since:     time.Now().AddDate(0, -1, 0),
+	})
+	if err != nil {
+		log.Fatalf("Whoops: %v", err)
+	}
+
+	for _, txn := range txns {
+		fmt.Println(txn.TransactionTime, txn.Amount.MinorUnits, txn.Amount.Currency, txn.CounterPartyName)
+	}
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/astravexton/starling"
+)
+
+// CSVField names a column that WriteCSV can write. The field name is used as
+// the column header.
+type CSVField string
+
+// Fields available to CSVLayout.
+const (
+	FieldDate         CSVField = "Date"         // Transaction date, formatted with CSVLayout.DateFormat
+	FieldSettled      CSVField = "Settled"      // Settlement date, formatted with CSVLayout.DateFormat
+	FieldCounterParty CSVField = "CounterParty" // Name of the other party
+	FieldReference    CSVField = "Reference"
+	FieldAmount       CSVField = "Amount" // Signed amount, negative for money out
+	FieldDebit        CSVField = "Debit"  // Amount of money out, empty for money in
+	FieldCredit       CSVField = "Credit" // Amount of money in, empty for money out
+	FieldCurrency     CSVField = "Currency"
+	FieldDirection    CSVField = "Direction"
+	FieldStatus       CSVField = "Status"
+	FieldSource       CSVField = "Source"
+	FieldCategory     CSVField = "SpendingCategory"
+	FieldNote         CSVField = "Note"
+	FieldID           CSVField = "FeedItemUID"
+)
+
+// DefaultCSVFields are the columns written when a CSVLayout does not list any.
+var DefaultCSVFields = []CSVField{
+	FieldDate, FieldCounterParty, FieldReference, FieldAmount, FieldCurrency, FieldStatus, FieldCategory, FieldID,
+}
+
+// CSVLayout controls the file written by WriteCSV.
+type CSVLayout struct {
+	Fields     []CSVField // Columns in order; defaults to DefaultCSVFields
+	Comma      rune       // Field delimiter; defaults to ','
+	DateFormat string     // Layout used for dates; defaults to "2006-01-02"
+	NoHeader   bool       // Omit the header row
+}
+
+// WriteCSV writes the items as CSV using the given layout. A nil layout writes
+// DefaultCSVFields with a header row.
+func WriteCSV(w io.Writer, items []starling.FeedItem, layout *CSVLayout) error {
+	l := CSVLayout{}
+	if layout != nil {
+		l = *layout
+	}
+	if len(l.Fields) == 0 {
+		l.Fields = DefaultCSVFields
+	}
+	if l.DateFormat == "" {
+		l.DateFormat = "2006-01-02"
+	}
+
+	cw := csv.NewWriter(w)
+	if l.Comma != 0 {
+		cw.Comma = l.Comma
+	}
+
+	if !l.NoHeader {
+		row := make([]string, len(l.Fields))
+		for i, f := range l.Fields {
+			row[i] = string(f)
+		}
+		cw.Write(row)
+	}
+
+	for _, it := range included(items) {
+		row := make([]string, len(l.Fields))
+		for i, f := range l.Fields {
+			v, err := l.value(f, it)
+			if err != nil {
+				return err
+			}
+			row[i] = v
+		}
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (l *CSVLayout) value(f CSVField, it starling.FeedItem) (string, error) {
+	switch f {
+	case FieldDate:
+		return it.TransactionTime.UTC().Format(l.DateFormat), nil
+	case FieldSettled:
+		if it.SettlementTime.IsZero() {
+			return "", nil
+		}
+		return it.SettlementTime.UTC().Format(l.DateFormat), nil
+	case FieldCounterParty:
+		return payee(it), nil
+	case FieldReference:
+		return it.Reference, nil
+	case FieldAmount:
+		return signedAmount(it), nil
+	case FieldDebit:
+		if it.Direction != "OUT" {
+			return "", nil
+		}
+		return formatMinor(it.Amount.MinorUnits, it.Amount.Currency), nil
+	case FieldCredit:
+		if it.Direction == "OUT" {
+			return "", nil
+		}
+		return formatMinor(it.Amount.MinorUnits, it.Amount.Currency), nil
+	case FieldCurrency:
+		return it.Amount.Currency, nil
+	case FieldDirection:
+		return it.Direction, nil
+	case FieldStatus:
+		return it.Status, nil
+	case FieldSource:
+		return it.Source, nil
+	case FieldCategory:
+		return string(it.SpendingCategory), nil
+	case FieldNote:
+		return it.UserNote, nil
+	case FieldID:
+		return it.FeedItemUID, nil
+	}
+	return "", fmt.Errorf("unknown CSV field %q", f)
+}
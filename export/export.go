@@ -0,0 +1,69 @@
+/*
+Package export writes Starling feed items in formats understood by accounting
+tools: OFX 2.x, QIF, CSV, Ledger/hledger journals and Beancount.
+
+	act := export.Account{Summary: summary, ID: *ids}
+	err := export.WriteOFX(w, act, items, nil)
+
+Items are written in transaction time order. Declined and reversed items are
+skipped as no money moved. Amounts are signed according to the item Direction,
+so money leaving the account is negative, and are scaled from minor units
+according to the currency.
+*/
+package export
+
+import (
+	"sort"
+
+	"github.com/astravexton/starling"
+)
+
+// Account describes the account that feed items belong to.
+type Account struct {
+	Summary starling.AccountSummary
+	ID      starling.AccountID
+}
+
+// formatMinor formats minor units as a decimal number in major units.
+func formatMinor(minor int64, currency string) string {
+	return starling.Money{Currency: currency, MinorUnits: minor}.Decimal()
+}
+
+// signedMinor returns the amount of the item in minor units, negative if the
+// money left the account.
+func signedMinor(item starling.FeedItem) int64 {
+	if item.Direction == "OUT" {
+		return -item.Amount.MinorUnits
+	}
+	return item.Amount.MinorUnits
+}
+
+// signedAmount returns the formatted amount of the item, negative if the money
+// left the account.
+func signedAmount(item starling.FeedItem) string {
+	return formatMinor(signedMinor(item), item.Amount.Currency)
+}
+
+// payee returns the best available name for the other party to the transaction.
+func payee(item starling.FeedItem) string {
+	if item.CounterPartyName != "" {
+		return item.CounterPartyName
+	}
+	if item.CounterPartySubEntityName != "" {
+		return item.CounterPartySubEntityName
+	}
+	return item.Reference
+}
+
+// included returns the items to export in transaction time order.
+func included(items []starling.FeedItem) []starling.FeedItem {
+	out := make([]starling.FeedItem, 0, len(items))
+	for _, it := range items {
+		if it.Status == "DECLINED" || it.Status == "REVERSED" {
+			continue
+		}
+		out = append(out, it)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].TransactionTime.Before(out[j].TransactionTime) })
+	return out
+}
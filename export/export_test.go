@@ -0,0 +1,226 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astravexton/starling"
+)
+
+const cross = "✗"
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+var testAccount = Account{
+	Summary: starling.AccountSummary{
+		UID:             "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0",
+		DefaultCategory: "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0",
+		Currency:        "GBP",
+	},
+	ID: starling.AccountID{ID: "12345678", BankID: "608371", IBAN: "GB00SRLG60837112345678", BIC: "SRLGGB2L"},
+}
+
+var testItems = []starling.FeedItem{
+	{
+		FeedItemUID:      "199c2bba-9f4d-4b20-b5df-4de440411b03",
+		Amount:           starling.Amount{Currency: "GBP", MinorUnits: 250000},
+		Direction:        "IN",
+		TransactionTime:  time.Date(2020, 6, 1, 8, 0, 0, 0, time.UTC),
+		SettlementTime:   time.Date(2020, 6, 1, 8, 0, 0, 0, time.UTC),
+		Source:           "FASTER_PAYMENTS_IN",
+		Status:           "SETTLED",
+		CounterPartyName: "ACME Widgets Limited",
+		Reference:        "SALARY JUNE",
+		SpendingCategory: "INCOME",
+	},
+	{
+		FeedItemUID:      "dbb59f1c-39e6-4558-87ba-11c142965393",
+		Amount:           starling.Amount{Currency: "GBP", MinorUnits: 1234},
+		Direction:        "OUT",
+		TransactionTime:  time.Date(2020, 6, 2, 12, 30, 0, 0, time.UTC),
+		Source:           "MASTER_CARD",
+		SourceSubType:    "CONTACTLESS",
+		Status:           "PENDING",
+		CounterPartyName: "M&S <Simply Food>",
+		Reference:        "M&S SIMPLY FOOD\\LONDON",
+		SpendingCategory: "GROCERIES",
+		UserNote:         "lunch",
+	},
+	{
+		FeedItemUID:      "6f1b3a2e-45c2-4f3e-9a0e-2d9c8e1f4b77",
+		Amount:           starling.Amount{Currency: "GBP", MinorUnits: 5},
+		Direction:        "OUT",
+		TransactionTime:  time.Date(2020, 6, 2, 13, 0, 0, 0, time.UTC),
+		Source:           "MASTER_CARD",
+		Status:           "DECLINED",
+		CounterPartyName: "Declined Merchant",
+	},
+	{
+		FeedItemUID:      "32f8ffc4-d12c-43fe-9d1b-61faf7243143",
+		Amount:           starling.Amount{Currency: "GBP", MinorUnits: 4599},
+		Direction:        "OUT",
+		TransactionTime:  time.Date(2020, 6, 1, 9, 15, 0, 0, time.UTC),
+		SettlementTime:   time.Date(2020, 6, 1, 9, 15, 0, 0, time.UTC),
+		Source:           "DIRECT_DEBIT",
+		Status:           "SETTLED",
+		CounterPartyName: "Energy Supplier Plc With A Very Long Name",
+		Reference:        "ACC 000123",
+		SpendingCategory: "BILLS_AND_SERVICES",
+	},
+}
+
+// checkGolden compares got with the named file in testdata, rewriting the file
+// when the -update flag is set.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal("should read the golden file", cross, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("should match %s %s\n%s", path, cross, got)
+	}
+}
+
+func TestFormatMinor(t *testing.T) {
+	cases := []struct {
+		minor    int64
+		currency string
+		want     string
+	}{
+		{1234, "GBP", "12.34"},
+		{-5, "EUR", "-0.05"},
+		{0, "GBP", "0.00"},
+		{1234, "JPY", "1234"},
+		{-1234, "jpy", "-1234"},
+		{1234, "BHD", "1.234"},
+		{7, "KWD", "0.007"},
+	}
+
+	for _, c := range cases {
+		if got := formatMinor(c.minor, c.currency); got != c.want {
+			t.Errorf("should format %d %s as %s %s %s", c.minor, c.currency, c.want, cross, got)
+		}
+	}
+}
+
+func TestWriteOFX(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteOFX(&buf, testAccount, testItems, &OFXOptions{
+		Generated: time.Date(2020, 6, 3, 0, 0, 0, 0, time.UTC),
+		Balance: &starling.Balance{
+			Cleared:   starling.Amount{Currency: "GBP", MinorUnits: 245401},
+			Effective: starling.Amount{Currency: "GBP", MinorUnits: 244167},
+		},
+	})
+	if err != nil {
+		t.Fatal("should write OFX without error", cross, err)
+	}
+	checkGolden(t, "statement.ofx", buf.Bytes())
+
+	var doc struct {
+		Account struct {
+			BankID string `xml:"BANKID"`
+			ID     string `xml:"ACCTID"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKACCTFROM"`
+		Transactions []struct {
+			Type   string `xml:"TRNTYPE"`
+			Amount string `xml:"TRNAMT"`
+			FITID  string `xml:"FITID"`
+			Name   string `xml:"NAME"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal("should write well-formed XML", cross, err)
+	}
+
+	if doc.Account.BankID != "608371" || doc.Account.ID != "12345678" {
+		t.Error("should identify the account", cross, doc.Account)
+	}
+	if len(doc.Transactions) != 3 {
+		t.Fatal("should skip declined transactions", cross, len(doc.Transactions))
+	}
+	last := doc.Transactions[2]
+	if last.FITID != testItems[1].FeedItemUID || last.Amount != "-12.34" || last.Name != "M&S <Simply Food>" || last.Type != "POS" {
+		t.Error("should round-trip the card transaction", cross, last)
+	}
+}
+
+func TestWriteQIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteQIF(&buf, testItems, nil); err != nil {
+		t.Fatal("should write QIF without error", cross, err)
+	}
+	checkGolden(t, "statement.qif", buf.Bytes())
+
+	// Read the records back and check the amounts.
+	var amounts []string
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		if strings.HasPrefix(sc.Text(), "T") {
+			amounts = append(amounts, sc.Text()[1:])
+		}
+	}
+	if strings.Join(amounts, " ") != "2500.00 -45.99 -12.34" {
+		t.Error("should write signed amounts in transaction order", cross, amounts)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Run("default layout", func(tc *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, testItems, nil); err != nil {
+			tc.Fatal("should write CSV without error", cross, err)
+		}
+		checkGolden(tc, "statement.csv", buf.Bytes())
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			tc.Fatal("should write valid CSV", cross, err)
+		}
+		if len(rows) != 4 || rows[3][1] != "M&S <Simply Food>" || rows[3][3] != "-12.34" {
+			tc.Error("should round-trip the rows", cross, rows)
+		}
+	})
+
+	t.Run("custom layout", func(tc *testing.T) {
+		var buf bytes.Buffer
+		err := WriteCSV(&buf, testItems, &CSVLayout{
+			Fields:     []CSVField{FieldDate, FieldDebit, FieldCredit, FieldNote},
+			Comma:      ';',
+			DateFormat: "02/01/2006",
+			NoHeader:   true,
+		})
+		if err != nil {
+			tc.Fatal("should write CSV without error", cross, err)
+		}
+
+		want := "01/06/2020;;2500.00;\n01/06/2020;45.99;;\n02/06/2020;12.34;;lunch\n"
+		if got := buf.String(); got != want {
+			tc.Error("should use the custom layout", cross, got)
+		}
+	})
+
+	t.Run("unknown field", func(tc *testing.T) {
+		err := WriteCSV(ioutil.Discard, testItems, &CSVLayout{Fields: []CSVField{"Nope"}})
+		if err == nil {
+			tc.Error("should reject an unknown field", cross)
+		}
+	})
+}
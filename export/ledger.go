@@ -0,0 +1,295 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astravexton/starling"
+)
+
+// LedgerAccounts maps feed items to the account names used in plain-text
+// accounting journals. Items are exported from the point of view of the
+// Starling account, so each transaction has a posting to Asset (or to a savings
+// goal account) and a balancing posting to an expense, income or asset account.
+//
+// Export the feed of the default category only: transfers to savings goals
+// already appear there and exporting the goal feeds as well would record them
+// twice.
+type LedgerAccounts struct {
+	Asset          string                               // Account for the Starling balance; defaults to "Assets:Starling"
+	Savings        string                               // Account for savings goals not listed in Goals; defaults to Asset + ":Savings"
+	Goals          map[string]string                    // Accounts for savings goals, keyed by the goal category UID
+	CounterParties map[string]string                    // Accounts keyed by CounterPartyName; these take precedence over Categories
+	Categories     map[starling.SpendingCategory]string // Accounts keyed by SpendingCategory
+	Expenses       string                               // Parent of generated expense accounts; defaults to "Expenses"
+	Income         string                               // Parent of generated income accounts; defaults to "Income"
+}
+
+// LedgerOptions controls the journal written by WriteLedger and WriteBeancount.
+type LedgerOptions struct {
+	Accounts     LedgerAccounts
+	Balance      *starling.Balance // If set, a balance assertion on Asset is written
+	BalanceDate  time.Time         // Date of the balance; defaults to the latest transaction
+	OpenAccounts bool              // Write Beancount open directives for every account used
+}
+
+// posting is one side of a journal transaction.
+type posting struct {
+	account string
+	minor   int64
+}
+
+// entry is a journal transaction with two postings, the second balancing the first.
+type entry struct {
+	date     time.Time
+	cleared  bool
+	payee    string
+	narr     string
+	uid      string
+	own      posting
+	otherAcc string
+}
+
+func (a *LedgerAccounts) withDefaults() LedgerAccounts {
+	l := *a
+	if l.Asset == "" {
+		l.Asset = "Assets:Starling"
+	}
+	if l.Savings == "" {
+		l.Savings = l.Asset + ":Savings"
+	}
+	if l.Expenses == "" {
+		l.Expenses = "Expenses"
+	}
+	if l.Income == "" {
+		l.Income = "Income"
+	}
+	return l
+}
+
+// goal returns the account for a savings goal category, and whether the
+// category is a known goal.
+func (a *LedgerAccounts) goal(uid string) (string, bool) {
+	acc, ok := a.Goals[uid]
+	return acc, ok
+}
+
+// counterAccount returns the account for the other side of an item.
+func (a *LedgerAccounts) counterAccount(it starling.FeedItem, ownIsGoal bool) string {
+	if it.CounterPartyType == "CATEGORY" {
+		if acc, ok := a.goal(it.CounterPartyUID); ok {
+			return acc
+		}
+		if ownIsGoal {
+			return a.Asset
+		}
+		return a.Savings
+	}
+
+	if acc, ok := a.CounterParties[it.CounterPartyName]; ok {
+		return acc
+	}
+	if acc, ok := a.Categories[it.SpendingCategory]; ok {
+		return acc
+	}
+
+	parent := a.Expenses
+	if it.Direction == "IN" {
+		parent = a.Income
+	}
+	if it.SpendingCategory == "" {
+		return parent + ":Uncategorised"
+	}
+	return parent + ":" + accountComponent(string(it.SpendingCategory))
+}
+
+// accountComponent converts a Starling enum value such as BILLS_AND_SERVICES to
+// an account name component such as BillsAndServices.
+func accountComponent(s string) string {
+	var b strings.Builder
+	for _, w := range strings.Split(strings.ToLower(s), "_") {
+		if w == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// entries converts the items into journal transactions, adding a transfer for
+// each round-up.
+func entries(items []starling.FeedItem, accts LedgerAccounts) []entry {
+	var es []entry
+	for _, it := range included(items) {
+		own, ownIsGoal := accts.goal(it.CategoryUID)
+		if !ownIsGoal {
+			own = accts.Asset
+		}
+
+		es = append(es, entry{
+			date:     it.TransactionTime,
+			cleared:  it.Status == "SETTLED",
+			payee:    payee(it),
+			narr:     it.Reference,
+			uid:      it.FeedItemUID,
+			own:      posting{account: own, minor: signedMinor(it)},
+			otherAcc: accts.counterAccount(it, ownIsGoal),
+		})
+
+		if ru := it.RoundUp; ru.Amount.MinorUnits > 0 {
+			goal, ok := accts.goal(ru.GoalCategoryUID)
+			if !ok {
+				goal = accts.Savings
+			}
+			es = append(es, entry{
+				date:     it.TransactionTime,
+				cleared:  it.Status == "SETTLED",
+				payee:    "Round-up",
+				narr:     payee(it),
+				uid:      it.FeedItemUID,
+				own:      posting{account: accts.Asset, minor: -ru.Amount.MinorUnits},
+				otherAcc: goal,
+			})
+		}
+	}
+	return es
+}
+
+// currencyOf returns the currency of the items, defaulting to GBP.
+func currencyOf(items []starling.FeedItem) string {
+	for _, it := range items {
+		if it.Amount.Currency != "" {
+			return it.Amount.Currency
+		}
+	}
+	return "GBP"
+}
+
+// balanceDate returns the date of the balance assertion.
+func balanceDate(opts *LedgerOptions, es []entry) time.Time {
+	if !opts.BalanceDate.IsZero() {
+		return opts.BalanceDate
+	}
+	if len(es) > 0 {
+		return es[len(es)-1].date
+	}
+	return time.Now()
+}
+
+// WriteLedger writes the items as a journal readable by both Ledger and hledger.
+// Settled items are marked cleared (*) and pending items pending (!). Each
+// transaction is tagged with its FeedItemUID.
+func WriteLedger(w io.Writer, items []starling.FeedItem, opts *LedgerOptions) error {
+	if opts == nil {
+		opts = &LedgerOptions{}
+	}
+	accts := opts.Accounts.withDefaults()
+	cur := currencyOf(items)
+	es := entries(items, accts)
+
+	bw := bufio.NewWriter(w)
+	for _, e := range es {
+		flag := "!"
+		if e.cleared {
+			flag = "*"
+		}
+		fmt.Fprintf(bw, "%s %s %s\n", e.date.UTC().Format("2006/01/02"), flag, ledgerText(e.payee))
+		if e.narr != "" {
+			fmt.Fprintf(bw, "    ; %s\n", ledgerText(e.narr))
+		}
+		fmt.Fprintf(bw, "    ; feed-item: %s\n", e.uid)
+		fmt.Fprintf(bw, "    %-40s  %s %s\n", e.own.account, formatMinor(e.own.minor, cur), cur)
+		fmt.Fprintf(bw, "    %s\n\n", e.otherAcc)
+	}
+
+	if b := opts.Balance; b != nil {
+		fmt.Fprintf(bw, "%s * Balance assertion\n", balanceDate(opts, es).UTC().Format("2006/01/02"))
+		fmt.Fprintf(bw, "    %-40s  0 %s = %s %s\n", accts.Asset, b.Effective.Currency,
+			formatMinor(b.Effective.MinorUnits, b.Effective.Currency), b.Effective.Currency)
+	}
+	return bw.Flush()
+}
+
+// WriteBeancount writes the items as Beancount transactions. Settled items are
+// flagged complete (*) and pending items incomplete (!). Each transaction has a
+// feed_item metadata entry holding its FeedItemUID.
+func WriteBeancount(w io.Writer, items []starling.FeedItem, opts *LedgerOptions) error {
+	if opts == nil {
+		opts = &LedgerOptions{}
+	}
+	accts := opts.Accounts.withDefaults()
+	cur := currencyOf(items)
+	es := entries(items, accts)
+
+	bw := bufio.NewWriter(w)
+	if opts.OpenAccounts && len(es) > 0 {
+		seen := map[string]bool{accts.Asset: true}
+		for _, e := range es {
+			seen[e.own.account] = true
+			seen[e.otherAcc] = true
+		}
+		names := make([]string, 0, len(seen))
+		for n := range seen {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		opened := es[0].date.UTC().Format("2006-01-02")
+		for _, n := range names {
+			fmt.Fprintf(bw, "%s open %s\n", opened, n)
+		}
+		fmt.Fprintln(bw)
+	}
+
+	for _, e := range es {
+		flag := "!"
+		if e.cleared {
+			flag = "*"
+		}
+		fmt.Fprintf(bw, "%s %s %s %s\n", e.date.UTC().Format("2006-01-02"), flag, beancountString(e.payee), beancountString(e.narr))
+		fmt.Fprintf(bw, "  feed_item: %s\n", beancountString(e.uid))
+		fmt.Fprintf(bw, "  %-40s  %s %s\n", e.own.account, formatMinor(e.own.minor, cur), cur)
+		fmt.Fprintf(bw, "  %s\n\n", e.otherAcc)
+	}
+
+	if b := opts.Balance; b != nil {
+		// Beancount checks balances at the start of the day, so assert the
+		// closing balance on the following day. The assertion covers the
+		// accounts beneath Asset too, so it includes the savings goals kept
+		// there as well as the Starling balance.
+		date := balanceDate(opts, es).UTC().AddDate(0, 0, 1)
+		total := b.Effective.MinorUnits + subAccountTotal(es, accts.Asset)
+		fmt.Fprintf(bw, "%s balance %-40s  %s %s\n", date.Format("2006-01-02"), accts.Asset,
+			formatMinor(total, b.Effective.Currency), b.Effective.Currency)
+	}
+	return bw.Flush()
+}
+
+// subAccountTotal returns the sum of the postings to accounts beneath parent.
+func subAccountTotal(es []entry, parent string) int64 {
+	prefix := parent + ":"
+	var total int64
+	for _, e := range es {
+		if strings.HasPrefix(e.own.account, prefix) {
+			total += e.own.minor
+		}
+		if strings.HasPrefix(e.otherAcc, prefix) {
+			total -= e.own.minor
+		}
+	}
+	return total
+}
+
+// ledgerText removes characters that would end a Ledger payee or comment early.
+func ledgerText(s string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r", " ", "\n", " ", ";", ",").Replace(s))
+}
+
+// beancountString quotes s as a Beancount string.
+func beancountString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astravexton/starling"
+)
+
+const testGoal = "a2f4e1c6-7d8b-4c3a-9e5f-1b2c3d4e5f60"
+
+var ledgerItems = append([]starling.FeedItem{
+	{
+		FeedItemUID:      "5e7d9c1a-2b3f-4a6e-8c0d-9f1e2a3b4c5d",
+		CategoryUID:      testAccount.Summary.DefaultCategory,
+		Amount:           starling.Amount{Currency: "GBP", MinorUnits: 350},
+		Direction:        "OUT",
+		TransactionTime:  time.Date(2020, 6, 2, 17, 45, 0, 0, time.UTC),
+		Source:           "MASTER_CARD",
+		Status:           "SETTLED",
+		CounterPartyName: "Corner Coffee",
+		SpendingCategory: "EATING_OUT",
+		RoundUp:          starling.FeedRoundUp{GoalCategoryUID: testGoal, Amount: starling.Amount{Currency: "GBP", MinorUnits: 50}},
+	},
+	{
+		FeedItemUID:      "8a9b0c1d-2e3f-4a5b-6c7d-8e9f0a1b2c3d",
+		CategoryUID:      testAccount.Summary.DefaultCategory,
+		Amount:           starling.Amount{Currency: "GBP", MinorUnits: 10000},
+		Direction:        "OUT",
+		TransactionTime:  time.Date(2020, 6, 2, 18, 0, 0, 0, time.UTC),
+		Source:           "INTERNAL_TRANSFER",
+		Status:           "SETTLED",
+		CounterPartyType: "CATEGORY",
+		CounterPartyUID:  testGoal,
+		CounterPartyName: "Holiday",
+	},
+}, testItems...)
+
+var ledgerOptions = &LedgerOptions{
+	Accounts: LedgerAccounts{
+		Goals:          map[string]string{testGoal: "Assets:Starling:Holiday"},
+		CounterParties: map[string]string{"ACME Widgets Limited": "Income:Salary"},
+		Categories:     map[starling.SpendingCategory]string{starling.SpendingCategoryBillsAndServices: "Expenses:Utilities"},
+	},
+	Balance:     &starling.Balance{Effective: starling.Amount{Currency: "GBP", MinorUnits: 233767}},
+	BalanceDate: time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC),
+}
+
+func TestWriteLedger(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteLedger(&buf, ledgerItems, ledgerOptions); err != nil {
+		t.Fatal("should write the journal without error", cross, err)
+	}
+	checkGolden(t, "journal.ledger", buf.Bytes())
+
+	got := buf.String()
+	for _, want := range []string{
+		"    Income:Salary\n",
+		"    Expenses:Utilities\n",
+		"    Expenses:Groceries\n",
+		"2020/06/02 * Round-up\n",
+		"    Assets:Starling:Holiday\n",
+		"0 GBP = 2337.67 GBP\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("should contain %q %s", want, cross)
+		}
+	}
+	if strings.Contains(got, "Declined Merchant") {
+		t.Error("should skip declined items", cross)
+	}
+}
+
+func TestWriteBeancount(t *testing.T) {
+	opts := *ledgerOptions
+	opts.OpenAccounts = true
+
+	var buf bytes.Buffer
+	if err := WriteBeancount(&buf, ledgerItems, &opts); err != nil {
+		t.Fatal("should write the journal without error", cross, err)
+	}
+	checkGolden(t, "journal.beancount", buf.Bytes())
+
+	got := buf.String()
+	for _, want := range []string{
+		"2020-06-01 open Assets:Starling\n",
+		"2020-06-02 ! \"M&S <Simply Food>\" \"M&S SIMPLY FOOD\\\\LONDON\"\n",
+		// Beancount balances Assets:Starling with its sub-accounts, so the
+		// 2337.67 effective balance is asserted with the 100.50 moved to Holiday.
+		"2020-06-03 balance Assets:Starling                           2438.17 GBP\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("should contain %q %s", want, cross)
+		}
+	}
+}
+
+func TestAccountComponent(t *testing.T) {
+	cases := map[string]string{
+		"BILLS_AND_SERVICES": "BillsAndServices",
+		"EATING_OUT":         "EatingOut",
+		"INCOME":             "Income",
+	}
+	for in, want := range cases {
+		if got := accountComponent(in); got != want {
+			t.Errorf("should convert %s to %s %s %s", in, want, cross, got)
+		}
+	}
+}
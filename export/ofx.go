@@ -0,0 +1,188 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/astravexton/starling"
+)
+
+// ofxDateFormat is the OFX datetime format, written in UTC.
+const ofxDateFormat = "20060102150405.000"
+
+// ofxNameLength is the maximum length of the NAME element.
+const ofxNameLength = 32
+
+// OFXOptions controls the statement written by WriteOFX.
+type OFXOptions struct {
+	Generated time.Time         // Time the statement was generated; defaults to the current time
+	Start     time.Time         // Start of the statement period; defaults to the earliest transaction
+	End       time.Time         // End of the statement period; defaults to the latest transaction
+	Balance   *starling.Balance // If set, included as the ledger and available balances
+}
+
+// WriteOFX writes the items as an OFX 2.1 bank statement for the account. The
+// FITID of each transaction is its FeedItemUID, so importing the same item twice
+// is recognised as a duplicate.
+func WriteOFX(w io.Writer, act Account, items []starling.FeedItem, opts *OFXOptions) error {
+	if opts == nil {
+		opts = &OFXOptions{}
+	}
+	items = included(items)
+
+	generated := opts.Generated
+	if generated.IsZero() {
+		generated = time.Now()
+	}
+	start, end := opts.Start, opts.End
+	if len(items) > 0 {
+		if start.IsZero() {
+			start = items[0].TransactionTime
+		}
+		if end.IsZero() {
+			end = items[len(items)-1].TransactionTime
+		}
+	}
+	if start.IsZero() {
+		start = generated
+	}
+	if end.IsZero() {
+		end = generated
+	}
+
+	ow := &ofxWriter{w: bufio.NewWriter(w)}
+	ow.raw(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>`)
+	ow.raw(`<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`)
+	ow.open("OFX")
+
+	ow.open("SIGNONMSGSRSV1")
+	ow.open("SONRS")
+	ow.open("STATUS")
+	ow.elem("CODE", "0")
+	ow.elem("SEVERITY", "INFO")
+	ow.close("STATUS")
+	ow.elem("DTSERVER", ofxDate(generated))
+	ow.elem("LANGUAGE", "ENG")
+	ow.close("SONRS")
+	ow.close("SIGNONMSGSRSV1")
+
+	ow.open("BANKMSGSRSV1")
+	ow.open("STMTTRNRS")
+	ow.elem("TRNUID", "0")
+	ow.open("STATUS")
+	ow.elem("CODE", "0")
+	ow.elem("SEVERITY", "INFO")
+	ow.close("STATUS")
+	ow.open("STMTRS")
+	ow.elem("CURDEF", act.Summary.Currency)
+
+	ow.open("BANKACCTFROM")
+	ow.elem("BANKID", act.ID.BankID)
+	ow.elem("ACCTID", act.ID.ID)
+	ow.elem("ACCTTYPE", "CHECKING")
+	ow.close("BANKACCTFROM")
+
+	ow.open("BANKTRANLIST")
+	ow.elem("DTSTART", ofxDate(start))
+	ow.elem("DTEND", ofxDate(end))
+	for _, it := range items {
+		ow.open("STMTTRN")
+		ow.elem("TRNTYPE", ofxType(it))
+		ow.elem("DTPOSTED", ofxDate(it.TransactionTime))
+		ow.elem("TRNAMT", signedAmount(it))
+		ow.elem("FITID", it.FeedItemUID)
+		ow.elem("NAME", truncate(payee(it), ofxNameLength))
+		if it.Reference != "" {
+			ow.elem("MEMO", it.Reference)
+		}
+		ow.close("STMTTRN")
+	}
+	ow.close("BANKTRANLIST")
+
+	if b := opts.Balance; b != nil {
+		ow.open("LEDGERBAL")
+		ow.elem("BALAMT", formatMinor(b.Cleared.MinorUnits, b.Cleared.Currency))
+		ow.elem("DTASOF", ofxDate(generated))
+		ow.close("LEDGERBAL")
+		ow.open("AVAILBAL")
+		ow.elem("BALAMT", formatMinor(b.Effective.MinorUnits, b.Effective.Currency))
+		ow.elem("DTASOF", ofxDate(generated))
+		ow.close("AVAILBAL")
+	}
+
+	ow.close("STMTRS")
+	ow.close("STMTTRNRS")
+	ow.close("BANKMSGSRSV1")
+	ow.close("OFX")
+	return ow.flush()
+}
+
+// ofxType returns the OFX transaction type for an item.
+func ofxType(item starling.FeedItem) string {
+	switch {
+	case item.Source == "DIRECT_DEBIT":
+		return "DIRECTDEBIT"
+	case item.Source == "MASTER_CARD" && item.SourceSubType == "ATM":
+		return "ATM"
+	case item.Source == "MASTER_CARD" && item.Direction == "OUT":
+		return "POS"
+	case item.Direction == "OUT":
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+func ofxDate(t time.Time) string {
+	return t.UTC().Format(ofxDateFormat) + "[0:GMT]"
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	r := []rune(strings.TrimSpace(s))
+	if len(r) > n {
+		r = r[:n]
+	}
+	return strings.TrimSpace(string(r))
+}
+
+// ofxWriter writes indented OFX elements, remembering the first error.
+type ofxWriter struct {
+	w     *bufio.Writer
+	depth int
+	err   error
+}
+
+func (o *ofxWriter) raw(s string) {
+	if o.err == nil {
+		_, o.err = fmt.Fprintln(o.w, s)
+	}
+}
+
+func (o *ofxWriter) indent() string { return strings.Repeat("  ", o.depth) }
+
+func (o *ofxWriter) open(name string) {
+	o.raw(o.indent() + "<" + name + ">")
+	o.depth++
+}
+
+func (o *ofxWriter) close(name string) {
+	o.depth--
+	o.raw(o.indent() + "</" + name + ">")
+}
+
+func (o *ofxWriter) elem(name, value string) {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	o.raw(o.indent() + "<" + name + ">" + b.String() + "</" + name + ">")
+}
+
+func (o *ofxWriter) flush() error {
+	if o.err != nil {
+		return o.err
+	}
+	return o.w.Flush()
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/astravexton/starling"
+)
+
+// QIFOptions controls the file written by WriteQIF.
+type QIFOptions struct {
+	DateFormat string // Layout used for the D field; defaults to day/month/year ("02/01/2006")
+}
+
+// WriteQIF writes the items as a QIF bank account file. QIF has no transaction
+// identifier, so the FeedItemUID is written to the N field to keep imports stable.
+func WriteQIF(w io.Writer, items []starling.FeedItem, opts *QIFOptions) error {
+	layout := "02/01/2006"
+	if opts != nil && opts.DateFormat != "" {
+		layout = opts.DateFormat
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "!Type:Bank")
+	for _, it := range included(items) {
+		fmt.Fprintln(bw, "D"+it.TransactionTime.UTC().Format(layout))
+		fmt.Fprintln(bw, "T"+signedAmount(it))
+		if it.Status == "SETTLED" {
+			fmt.Fprintln(bw, "CX")
+		}
+		fmt.Fprintln(bw, "N"+it.FeedItemUID)
+		fmt.Fprintln(bw, "P"+qifText(payee(it)))
+		if it.Reference != "" {
+			fmt.Fprintln(bw, "M"+qifText(it.Reference))
+		}
+		if it.SpendingCategory != "" {
+			fmt.Fprintln(bw, "L"+qifText(string(it.SpendingCategory)))
+		}
+		fmt.Fprintln(bw, "^")
+	}
+	return bw.Flush()
+}
+
+// qifText removes line breaks, which would end a QIF field early.
+func qifText(s string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r", " ", "\n", " ").Replace(s))
+}
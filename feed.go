@@ -3,7 +3,10 @@ package starling
 import (
 	"context"
 	"net/http"
+	"sort"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Feed is a slice of Items representing customer transactions
@@ -13,37 +16,37 @@ type feed struct {
 
 // Item is a single customer transaction in their feed
 type FeedItem struct {
-	FeedItemUID                       string      `json:"feedItemUid"`
-	CategoryUID                       string      `json:"categoryUid"`
-	AccountUID                        string      `json:"accountUid"`
-	Amount                            Amount      `json:"amount"`
-	SourceAmount                      Amount      `json:"sourceAmount"`
-	Direction                         string      `json:"direction"`
-	UpdatedAt                         time.Time   `json:"updatedAt"`
-	TransactionTime                   time.Time   `json:"transactionTime"`
-	SettlementTime                    time.Time   `json:"settlementTime"`
-	RetryAllocationUntilTime          time.Time   `json:"retryAllocationUntilTime"`
-	Source                            string      `json:"source"`
-	SourceSubType                     string      `json:"sourceSubType"`
-	Status                            string      `json:"status"`
-	TransactionApplicationUserUID     string      `json:"transactionApplicationUserUid"`
-	CounterPartyType                  string      `json:"counterPartyType"`
-	CounterPartyUID                   string      `json:"counterPartyUid"`
-	CounterPartyName                  string      `json:"counterPartyName"`
-	CounterPartySubEntityUID          string      `json:"counterPartySubEntityUid"`
-	CounterPartySubEntityName         string      `json:"counterPartySubEntityName"`
-	CounterPartySubEntityIdentifier   string      `json:"counterPartySubEntityIdentifier"`
-	CounterPartSubEntitySubIdentifier string      `json:"counterPartSubEntitySubIdentifier"`
-	ExchangeRate                      float64     `json:"exchangeRate"`
-	TotalFees                         float64     `json:"totalFees"`
-	TotalFeeAmount                    Amount      `json:"totalFeeAmount"`
-	Reference                         string      `json:"reference"`
-	Country                           string      `json:"country"`
-	SpendingCategory                  string      `json:"spendingCategory"`
-	UserNote                          string      `json:"userNote"`
-	RoundUp                           FeedRoundUp `json:"roundUp"`
-	HasAttachment                     bool        `json:"hasAttachment"`
-	ReceiptPresent                    bool        `json:"receiptPresent"`
+	FeedItemUID                       string           `json:"feedItemUid"`
+	CategoryUID                       string           `json:"categoryUid"`
+	AccountUID                        string           `json:"accountUid"`
+	Amount                            Amount           `json:"amount"`
+	SourceAmount                      Amount           `json:"sourceAmount"`
+	Direction                         string           `json:"direction"`
+	UpdatedAt                         time.Time        `json:"updatedAt"`
+	TransactionTime                   time.Time        `json:"transactionTime"`
+	SettlementTime                    time.Time        `json:"settlementTime"`
+	RetryAllocationUntilTime          time.Time        `json:"retryAllocationUntilTime"`
+	Source                            string           `json:"source"`
+	SourceSubType                     string           `json:"sourceSubType"`
+	Status                            string           `json:"status"`
+	TransactionApplicationUserUID     string           `json:"transactionApplicationUserUid"`
+	CounterPartyType                  string           `json:"counterPartyType"`
+	CounterPartyUID                   string           `json:"counterPartyUid"`
+	CounterPartyName                  string           `json:"counterPartyName"`
+	CounterPartySubEntityUID          string           `json:"counterPartySubEntityUid"`
+	CounterPartySubEntityName         string           `json:"counterPartySubEntityName"`
+	CounterPartySubEntityIdentifier   string           `json:"counterPartySubEntityIdentifier"`
+	CounterPartSubEntitySubIdentifier string           `json:"counterPartSubEntitySubIdentifier"`
+	ExchangeRate                      float64          `json:"exchangeRate"`
+	TotalFees                         float64          `json:"totalFees"` // Prefer TotalFeeAmount, which is exact
+	TotalFeeAmount                    Amount           `json:"totalFeeAmount"`
+	Reference                         string           `json:"reference"`
+	Country                           string           `json:"country"`
+	SpendingCategory                  SpendingCategory `json:"spendingCategory"`
+	UserNote                          string           `json:"userNote"`
+	RoundUp                           FeedRoundUp      `json:"roundUp"`
+	HasAttachment                     bool             `json:"hasAttachment"`
+	ReceiptPresent                    bool             `json:"receiptPresent"`
 }
 
 type FeedRoundUp struct {
@@ -87,3 +90,75 @@ func (c *Client) FeedItem(ctx context.Context, act, cat, itm string) (*FeedItem,
 	}
 	return &i, resp, nil
 }
+
+// feedChunk is the longest period requested from the API in a single call by FeedBetween and
+// SettledFeedBetween.
+var feedChunk = 31 * 24 * time.Hour
+
+// FeedBetween returns the feed items for a given account and category with a transaction time
+// between dr.From and dr.To. Unlike Feed, which returns items changed since a point in time, only
+// items transacted in the range are returned. Long ranges are split into several requests and the
+// results merged in transaction time order. It also returns the http response of the last request
+// in case this is required for further processing.
+func (c *Client) FeedBetween(ctx context.Context, act, cat string, dr DateRange) ([]FeedItem, *http.Response, error) {
+	return c.feedBetween(ctx, "/api/v2/feed/account/"+act+"/category/"+cat+"/transactions-between", dr)
+}
+
+// SettledFeedBetween returns the settled feed items across all categories of an account with a
+// transaction time between dr.From and dr.To. Long ranges are split into several requests and the
+// results merged in transaction time order. It also returns the http response of the last request
+// in case this is required for further processing.
+func (c *Client) SettledFeedBetween(ctx context.Context, act string, dr DateRange) ([]FeedItem, *http.Response, error) {
+	return c.feedBetween(ctx, "/api/v2/feed/account/"+act+"/settled-transactions-between", dr)
+}
+
+// feedBetween requests the feed items in dr from path in chunks of at most feedChunk.
+func (c *Client) feedBetween(ctx context.Context, path string, dr DateRange) ([]FeedItem, *http.Response, error) {
+	if dr.To.Before(dr.From) {
+		return nil, nil, errors.Errorf("invalid date range: %s is before %s", dr.To.Format(time.RFC3339), dr.From.Format(time.RFC3339))
+	}
+
+	var (
+		items []FeedItem
+		resp  *http.Response
+		seen  = make(map[string]bool)
+	)
+	for from := dr.From; ; {
+		to := from.Add(feedChunk)
+		if to.After(dr.To) {
+			to = dr.To
+		}
+
+		req, err := c.NewRequest("GET", path, nil)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		q := req.URL.Query()
+		q.Add("minTransactionTimestamp", from.Format(time.RFC3339Nano))
+		q.Add("maxTransactionTimestamp", to.Format(time.RFC3339Nano))
+		req.URL.RawQuery = q.Encode()
+
+		var f feed
+		resp, err = c.Do(ctx, req, &f)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		// Items at the boundary between chunks may be returned twice.
+		for _, it := range f.Items {
+			if !seen[it.FeedItemUID] {
+				seen[it.FeedItemUID] = true
+				items = append(items, it)
+			}
+		}
+
+		if !to.Before(dr.To) {
+			break
+		}
+		from = to
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].TransactionTime.Before(items[j].TransactionTime) })
+	return items, resp, nil
+}
@@ -333,3 +333,88 @@ func TestFeedItemForbidden(t *testing.T) {
 		t.Error("should not return an item")
 	}
 }
+
+func TestFeedBetween(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	defer func(d time.Duration) { feedChunk = d }(feedChunk)
+	feedChunk = 10 * 24 * time.Hour
+
+	base := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	var all []FeedItem
+	for i, days := range []int{0, 7, 10, 12, 25, 29} {
+		all = append(all, FeedItem{
+			FeedItemUID:     fmt.Sprintf("item-%d", i),
+			TransactionTime: base.AddDate(0, 0, days),
+		})
+	}
+
+	var chunks int
+	mux.HandleFunc("/api/v2/feed/account/30aa7ab8-4389-4658-a4f8-0bc6d0015ba0/category/c423ab8d-9a6a-44b2-8db6-ac6000fe58e0/transactions-between", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		chunks++
+
+		min, _ := time.Parse(time.RFC3339Nano, r.URL.Query().Get("minTransactionTimestamp"))
+		max, _ := time.Parse(time.RFC3339Nano, r.URL.Query().Get("maxTransactionTimestamp"))
+		if max.Sub(min) > feedChunk {
+			t.Error("should not request more than feedChunk at once", cross, min, max)
+		}
+
+		// Return the items in reverse order, inclusive of both bounds.
+		var f feed
+		for i := len(all) - 1; i >= 0; i-- {
+			if tt := all[i].TransactionTime; !tt.Before(min) && !tt.After(max) {
+				f.Items = append(f.Items, all[i])
+			}
+		}
+		json.NewEncoder(w).Encode(f)
+	})
+
+	dr := DateRange{From: base, To: base.AddDate(0, 0, 30)}
+	got, _, err := client.FeedBetween(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", dr)
+	checkNoError(t, err)
+
+	if chunks != 3 {
+		t.Error("should split the range into chunks", cross, chunks)
+	}
+	if len(got) != len(all) {
+		t.Fatal("should return each item once", cross, len(got))
+	}
+	for i := range got {
+		if got[i].FeedItemUID != all[i].FeedItemUID {
+			t.Error("should return items in transaction time order", cross, got[i].FeedItemUID)
+		}
+	}
+}
+
+func TestSettledFeedBetween(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/feed/account/30aa7ab8-4389-4658-a4f8-0bc6d0015ba0/settled-transactions-between", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		q := r.URL.Query()
+		if q.Get("minTransactionTimestamp") != "2020-06-01T00:00:00Z" || q.Get("maxTransactionTimestamp") != "2020-06-30T00:00:00Z" {
+			t.Error("should request the given range", cross, r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"feedItems":[{"feedItemUid":"dbb59f1c-39e6-4558-87ba-11c142965393","status":"SETTLED"}]}`)
+	})
+
+	dr := DateRange{From: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC)}
+	got, _, err := client.SettledFeedBetween(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", dr)
+	checkNoError(t, err)
+
+	if len(got) != 1 || got[0].FeedItemUID != "dbb59f1c-39e6-4558-87ba-11c142965393" {
+		t.Error("should return the settled items", cross, got)
+	}
+}
+
+func TestFeedBetweenInvalidRange(t *testing.T) {
+	client, _, _, teardown := setup()
+	defer teardown()
+
+	dr := DateRange{From: time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC), To: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)}
+	_, _, err := client.FeedBetween(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", dr)
+	checkHasError(t, err)
+}
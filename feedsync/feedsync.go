@@ -0,0 +1,182 @@
+/*
+Package feedsync incrementally synchronises Starling account feeds.
+
+A Syncer remembers, for each account category, the most recent UpdatedAt
+timestamp it has seen and the items it has already reported. Each call to
+Sync fetches only the changes since then and reports them as events:
+
+	s := feedsync.New(client, feedsync.NewFileStore("feed-cursors.json"))
+
+	events, err := s.Sync(ctx, act.UID, act.DefaultCategory)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		fmt.Println(e.Type, e.Item.FeedItemUID, e.Item.Status)
+	}
+
+Progress is saved to the Store after every successful Sync so that a restarted
+process resumes where it left off.
+*/
+package feedsync
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/astravexton/starling"
+	"github.com/pkg/errors"
+)
+
+// defaultRetain is how long items are remembered after their last update.
+const defaultRetain = 90 * 24 * time.Hour
+
+// EventType describes how a feed item changed.
+type EventType int
+
+const (
+	// Added is reported the first time an item is seen.
+	Added EventType = iota + 1
+	// Updated is reported when a known item changes, such as moving from PENDING to SETTLED.
+	Updated
+	// Removed is reported when a known item is reversed or declined.
+	Removed
+)
+
+func (t EventType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Updated:
+		return "updated"
+	case Removed:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// Event is a change to a feed item.
+type Event struct {
+	Type           EventType
+	Item           starling.FeedItem
+	PreviousStatus string // Status of the item when it was last seen; empty for Added events
+}
+
+// Feeder retrieves the items in a feed that have changed since a point in time.
+// It is implemented by *starling.Client.
+type Feeder interface {
+	Feed(ctx context.Context, act, cat string, since time.Time) ([]starling.FeedItem, *http.Response, error)
+}
+
+// Syncer reports changes to account feeds. It is safe for concurrent use.
+type Syncer struct {
+	Start  time.Time     // Where to start syncing a category with no stored cursor; defaults to the zero time
+	Retain time.Duration // How long items are remembered after their last update; defaults to 90 days
+
+	feeder Feeder
+	store  Store
+	mu     sync.Mutex
+}
+
+// New returns a Syncer that fetches feeds using f and saves its progress to store.
+func New(f Feeder, store Store) *Syncer {
+	return &Syncer{feeder: f, store: store}
+}
+
+// removedStatus reports whether an item with the given status no longer
+// represents money moving.
+func removedStatus(status string) bool {
+	return status == "REVERSED" || status == "DECLINED"
+}
+
+// Sync fetches the changes to a feed since the last call and returns them in the
+// order they were updated. The cursor is only saved once the feed has been fetched
+// successfully, so a failed Sync can simply be retried.
+func (s *Syncer) Sync(ctx context.Context, act, cat string) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := Key{AccountUID: act, CategoryUID: cat}
+	cur, err := s.store.Load(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load cursor")
+	}
+	if cur == nil {
+		cur = &Cursor{Since: s.Start}
+	}
+	if cur.Items == nil {
+		cur.Items = map[string]ItemState{}
+	}
+
+	items, _, err := s.feeder.Feed(ctx, act, cat, cur.Since)
+	if err != nil {
+		return nil, err
+	}
+	sortByUpdate(items)
+
+	var events []Event
+	for _, it := range items {
+		prev, known := cur.Items[it.FeedItemUID]
+		if known && !it.UpdatedAt.After(prev.UpdatedAt) && it.Status == prev.Status {
+			continue
+		}
+		cur.Items[it.FeedItemUID] = ItemState{UpdatedAt: it.UpdatedAt, Status: it.Status}
+
+		if it.UpdatedAt.After(cur.Since) {
+			cur.Since = it.UpdatedAt
+		}
+
+		switch {
+		case !known && removedStatus(it.Status):
+			// Never reported, so there is nothing to remove.
+		case !known:
+			events = append(events, Event{Type: Added, Item: it})
+		case removedStatus(it.Status) && !removedStatus(prev.Status):
+			events = append(events, Event{Type: Removed, Item: it, PreviousStatus: prev.Status})
+		case removedStatus(it.Status):
+			// Already reported as removed.
+		case removedStatus(prev.Status):
+			events = append(events, Event{Type: Added, Item: it, PreviousStatus: prev.Status})
+		default:
+			events = append(events, Event{Type: Updated, Item: it, PreviousStatus: prev.Status})
+		}
+	}
+
+	s.prune(cur)
+	if err := s.store.Save(key, cur); err != nil {
+		return nil, errors.Wrap(err, "unable to save cursor")
+	}
+	return events, nil
+}
+
+// Reset forgets the progress made on a feed so the next Sync starts again from Start.
+func (s *Syncer) Reset(act, cat string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Save(Key{AccountUID: act, CategoryUID: cat}, nil)
+}
+
+// prune forgets items that have not been updated within the retention window.
+// A pruned item that is updated again is reported as Added.
+func (s *Syncer) prune(cur *Cursor) {
+	retain := s.Retain
+	if retain <= 0 {
+		retain = defaultRetain
+	}
+
+	cutoff := cur.Since.Add(-retain)
+	for uid, st := range cur.Items {
+		if st.UpdatedAt.Before(cutoff) {
+			delete(cur.Items, uid)
+		}
+	}
+}
+
+// sortByUpdate orders items by UpdatedAt, keeping the feed order for ties.
+func sortByUpdate(items []starling.FeedItem) {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].UpdatedAt.Before(items[j].UpdatedAt) })
+}
@@ -0,0 +1,170 @@
+package feedsync
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/astravexton/starling"
+)
+
+const cross = "✗"
+
+// stubFeeder returns the items updated since the requested time and records the
+// times it was asked for.
+type stubFeeder struct {
+	items  []starling.FeedItem
+	err    error
+	sinces []time.Time
+}
+
+func (f *stubFeeder) Feed(ctx context.Context, act, cat string, since time.Time) ([]starling.FeedItem, *http.Response, error) {
+	f.sinces = append(f.sinces, since)
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	var out []starling.FeedItem
+	for _, it := range f.items {
+		if !it.UpdatedAt.Before(since) {
+			out = append(out, it)
+		}
+	}
+	return out, nil, nil
+}
+
+func (f *stubFeeder) set(uid, status string, updated time.Time) {
+	for i, it := range f.items {
+		if it.FeedItemUID == uid {
+			f.items[i].Status = status
+			f.items[i].UpdatedAt = updated
+			return
+		}
+	}
+	f.items = append(f.items, starling.FeedItem{FeedItemUID: uid, Status: status, UpdatedAt: updated})
+}
+
+func checkEvents(t *testing.T, got []Event, want ...Event) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("should return %d events %s %d", len(want), cross, len(got))
+	}
+	for i := range want {
+		if got[i].Type != want[i].Type || got[i].Item.FeedItemUID != want[i].Item.FeedItemUID || got[i].PreviousStatus != want[i].PreviousStatus {
+			t.Errorf("should return event %v %s %v", want[i], cross, got[i])
+		}
+	}
+}
+
+func event(typ EventType, uid, prev string) Event {
+	return Event{Type: typ, Item: starling.FeedItem{FeedItemUID: uid}, PreviousStatus: prev}
+}
+
+func TestSync(t *testing.T) {
+	base := time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	f := &stubFeeder{}
+	f.set("a", "PENDING", base)
+	f.set("b", "SETTLED", base.Add(time.Minute))
+	f.set("x", "DECLINED", base.Add(2*time.Minute))
+
+	s := New(f, &MemoryStore{})
+	s.Start = base.Add(-time.Hour)
+
+	events, err := s.Sync(ctx, "act", "cat")
+	if err != nil {
+		t.Fatal("should sync without error", cross, err)
+	}
+	checkEvents(t, events, event(Added, "a", ""), event(Added, "b", ""))
+	if !f.sinces[0].Equal(s.Start) {
+		t.Error("should start from Start", cross, f.sinces[0])
+	}
+
+	events, _ = s.Sync(ctx, "act", "cat")
+	checkEvents(t, events)
+	if !f.sinces[1].Equal(base.Add(2 * time.Minute)) {
+		t.Error("should resume from the latest update", cross, f.sinces[1])
+	}
+
+	f.set("a", "SETTLED", base.Add(time.Hour))
+	f.set("b", "REVERSED", base.Add(time.Hour))
+	f.set("c", "PENDING", base.Add(time.Hour))
+
+	events, _ = s.Sync(ctx, "act", "cat")
+	checkEvents(t, events, event(Updated, "a", "PENDING"), event(Removed, "b", "SETTLED"), event(Added, "c", ""))
+}
+
+func TestSyncError(t *testing.T) {
+	store := &MemoryStore{}
+	f := &stubFeeder{err: errors.New("unavailable")}
+
+	if _, err := New(f, store).Sync(context.Background(), "act", "cat"); err == nil {
+		t.Error("should return the feed error", cross)
+	}
+	if cur, _ := store.Load(Key{"act", "cat"}); cur != nil {
+		t.Error("should not save a cursor after a failure", cross, cur)
+	}
+}
+
+func TestSyncResumesFromFileStore(t *testing.T) {
+	base := time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "cursors.json")
+	ctx := context.Background()
+
+	f := &stubFeeder{}
+	f.set("a", "PENDING", base)
+
+	events, err := New(f, NewFileStore(path)).Sync(ctx, "act", "cat")
+	if err != nil {
+		t.Fatal("should sync without error", cross, err)
+	}
+	checkEvents(t, events, event(Added, "a", ""))
+
+	f.set("a", "SETTLED", base.Add(time.Hour))
+
+	// A new Syncer simulates a restarted process.
+	events, err = New(f, NewFileStore(path)).Sync(ctx, "act", "cat")
+	if err != nil {
+		t.Fatal("should sync without error", cross, err)
+	}
+	checkEvents(t, events, event(Updated, "a", "PENDING"))
+}
+
+func TestReset(t *testing.T) {
+	f := &stubFeeder{}
+	f.set("a", "SETTLED", time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC))
+	s := New(f, &MemoryStore{})
+	ctx := context.Background()
+
+	s.Sync(ctx, "act", "cat")
+	if err := s.Reset("act", "cat"); err != nil {
+		t.Fatal("should reset without error", cross, err)
+	}
+
+	events, _ := s.Sync(ctx, "act", "cat")
+	checkEvents(t, events, event(Added, "a", ""))
+}
+
+func TestPrune(t *testing.T) {
+	base := time.Date(2020, 6, 1, 9, 0, 0, 0, time.UTC)
+	s := &Syncer{Retain: 24 * time.Hour}
+	cur := &Cursor{
+		Since: base,
+		Items: map[string]ItemState{
+			"old": {UpdatedAt: base.Add(-48 * time.Hour)},
+			"new": {UpdatedAt: base.Add(-time.Hour)},
+		},
+	}
+
+	s.prune(cur)
+	if _, ok := cur.Items["old"]; ok {
+		t.Error("should forget items outside the retention window", cross)
+	}
+	if _, ok := cur.Items["new"]; !ok {
+		t.Error("should remember items inside the retention window", cross)
+	}
+}
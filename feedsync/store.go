@@ -0,0 +1,142 @@
+package feedsync
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/astravexton/starling/internal/atomicfile"
+	"github.com/pkg/errors"
+)
+
+// Key identifies a feed: a category of an account.
+type Key struct {
+	AccountUID  string
+	CategoryUID string
+}
+
+func (k Key) String() string { return k.AccountUID + "/" + k.CategoryUID }
+
+// ItemState is what a Syncer remembers about a feed item it has reported.
+type ItemState struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	Status    string    `json:"status"`
+}
+
+// Cursor records the progress of a Syncer through a feed.
+type Cursor struct {
+	Since time.Time            `json:"since"` // Latest UpdatedAt seen
+	Items map[string]ItemState `json:"items"` // Items seen, keyed by FeedItemUID
+}
+
+// Store persists cursors between runs. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	Load(key Key) (*Cursor, error)   // Returns a nil Cursor if none has been saved
+	Save(key Key, cur *Cursor) error // Saving a nil Cursor removes it
+}
+
+// MemoryStore is a Store that holds cursors in memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	cursors map[Key][]byte
+}
+
+// Load returns a copy of the saved cursor.
+func (s *MemoryStore) Load(key Key) (*Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.cursors[key]
+	if !ok {
+		return nil, nil
+	}
+	var cur Cursor
+	err := json.Unmarshal(b, &cur)
+	return &cur, err
+}
+
+// Save replaces the saved cursor.
+func (s *MemoryStore) Save(key Key, cur *Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cur == nil {
+		delete(s.cursors, key)
+		return nil
+	}
+	b, err := json.Marshal(cur)
+	if err != nil {
+		return err
+	}
+	if s.cursors == nil {
+		s.cursors = map[Key][]byte{}
+	}
+	s.cursors[key] = b
+	return nil
+}
+
+// FileStore is a Store that keeps all cursors as JSON in a single file.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore that reads and writes cursors at path. The
+// file is created on the first Save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the cursor for key from the file.
+func (s *FileStore) Load(key Key) (*Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursors, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return cursors[key.String()], nil
+}
+
+// Save writes the cursor for key to the file.
+func (s *FileStore) Save(key Key, cur *Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursors, err := s.read()
+	if err != nil {
+		return err
+	}
+	if cur == nil {
+		delete(cursors, key.String())
+	} else {
+		cursors[key.String()] = cur
+	}
+
+	b, err := json.Marshal(cursors)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode cursors")
+	}
+
+	return errors.Wrap(atomicfile.WriteFile(s.path, b), "unable to save cursors")
+}
+
+func (s *FileStore) read() (map[string]*Cursor, error) {
+	cursors := map[string]*Cursor{}
+
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return cursors, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read cursors")
+	}
+	if err := json.Unmarshal(b, &cursors); err != nil {
+		return nil, errors.Wrap(err, "unable to parse cursors")
+	}
+	return cursors, nil
+}
@@ -0,0 +1,83 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// TokenIdentity describes the access token used by the client
+type TokenIdentity struct {
+	AccountHolderUID string    `json:"accountHolderUid"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	ExpiresInSeconds int64     `json:"expiresInSeconds"`
+	Authenticated    bool      `json:"authenticated"` // True if the customer has authenticated recently enough to make payments
+	Scopes           []string  `json:"scopes"`
+}
+
+// ExpiresWithin reports whether the token expires within d of now. Use it to refresh a token or
+// ask the customer to renew their consent before calls start failing with an AuthError.
+func (t TokenIdentity) ExpiresWithin(d time.Duration) bool {
+	return !t.ExpiresAt.IsZero() && time.Until(t.ExpiresAt) < d
+}
+
+// HasScope reports whether the token was granted a scope, such as "pay-local:create".
+func (t TokenIdentity) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenIdentity returns the identity of the access token used by the client, including when it
+// expires and the scopes it grants. It also returns the http response in case this is required
+// for further processing. An error will be returned if unable to retrieve the identity from the
+// API.
+func (c *Client) TokenIdentity(ctx context.Context) (*TokenIdentity, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/identity/token", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ti *TokenIdentity
+	resp, err := c.Do(ctx, req, &ti)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ti, resp, nil
+}
+
+// AuthorisingIndividual returns the person who authorised the access token. For business and
+// joint accounts this identifies which of the people with access to the account gave consent. It
+// also returns the http response in case this is required for further processing. An error will
+// be returned if unable to retrieve the details from the API.
+func (c *Client) AuthorisingIndividual(ctx context.Context) (*Individual, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/identity/authorising-individual", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ind *Individual
+	resp, err := c.Do(ctx, req, &ind)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return ind, resp, nil
+}
+
+// Logout revokes the access token and refresh token used by the client. The client cannot be used
+// again until the customer has authorised a new token. It returns the http response in case this
+// is required for further processing. An error is returned on failure.
+func (c *Client) Logout(ctx context.Context) (*http.Response, error) {
+	req, err := c.NewRequest("PUT", "/api/v2/identity/logout", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(ctx, req, nil)
+	return resp, err
+}
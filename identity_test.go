@@ -0,0 +1,71 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenIdentity(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	expires := time.Now().Add(10 * time.Minute).UTC().Truncate(time.Millisecond)
+
+	mux.HandleFunc("/api/v2/identity/token", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{
+			"accountHolderUid": "6f2b0c4a-1d3e-4f5a-8b6c-7d8e9f0a1b2c",
+			"expiresAt": %q,
+			"expiresInSeconds": 600,
+			"authenticated": true,
+			"scopes": ["account:read", "transaction:read"]
+		}`, expires.Format(time.RFC3339Nano))
+	})
+
+	got, _, err := client.TokenIdentity(context.Background())
+	checkNoError(t, err)
+
+	if got == nil || got.AccountHolderUID != "6f2b0c4a-1d3e-4f5a-8b6c-7d8e9f0a1b2c" || !got.ExpiresAt.Equal(expires) {
+		t.Fatal("should return the token identity", cross, got)
+	}
+	if !got.ExpiresWithin(time.Hour) || got.ExpiresWithin(time.Minute) {
+		t.Error("should report when the token expires", cross, got.ExpiresAt)
+	}
+	if !got.HasScope("transaction:read") || got.HasScope("pay-local:create") {
+		t.Error("should report the scopes of the token", cross, got.Scopes)
+	}
+}
+
+func TestAuthorisingIndividual(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/identity/authorising-individual", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"firstName":"Jane","lastName":"Smith"}`)
+	})
+
+	got, _, err := client.AuthorisingIndividual(context.Background())
+	checkNoError(t, err)
+
+	if got == nil || got.FirstName != "Jane" {
+		t.Error("should return the authorising individual", cross, got)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/identity/logout", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	resp, err := client.Logout(context.Background())
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusOK)
+}
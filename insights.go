@@ -0,0 +1,280 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SpendingGrouping determines how spending is broken down
+type SpendingGrouping int
+
+// Ways of breaking down spending
+const (
+	ByCounterParty SpendingGrouping = iota
+	ByCategory
+	ByCountry
+	ByMerchant
+)
+
+// SpendingInsights is the spending on an account over a period broken down by counterparty,
+// category, country or merchant
+type SpendingInsights struct {
+	Period        string // Month of the insights, formatted as yyyy-mm; empty if computed from a feed
+	TotalSpent    Amount
+	TotalReceived Amount
+	NetSpend      Amount // TotalSpent less TotalReceived
+	Breakdown     []SpendingBreakdown
+}
+
+// SpendingBreakdown is the spending with a single counterparty, category, country or merchant.
+// Only the fields identifying the grouping that was requested are set.
+type SpendingBreakdown struct {
+	CounterPartyUID  string
+	CounterPartyType string
+	CounterPartyName string
+	SpendingCategory SpendingCategory
+	CountryCode      string
+	MerchantUID      string
+	MerchantName     string
+	TotalSpent       Amount
+	TotalReceived    Amount
+	NetSpend         Amount
+	Percentage       float64 // Percentage of the total spent over the period
+	TransactionCount int
+}
+
+// spendingInsights is the response from the spending insights API, which holds amounts as
+// floats in major units
+type spendingInsights struct {
+	Period        string  `json:"period"`
+	TotalSpent    float64 `json:"totalSpent"`
+	TotalReceived float64 `json:"totalReceived"`
+	NetSpend      float64 `json:"netSpend"`
+	Currency      string  `json:"currency"`
+	Breakdown     []struct {
+		CounterPartyUID  string           `json:"counterPartyUid"`
+		CounterPartyType string           `json:"counterPartyType"`
+		CounterPartyName string           `json:"counterPartyName"`
+		SpendingCategory SpendingCategory `json:"spendingCategory"`
+		CountryCode      string           `json:"countryCode"`
+		MerchantUID      string           `json:"merchantUid"`
+		MerchantName     string           `json:"merchantName"`
+		TotalSpent       float64          `json:"totalSpent"`
+		TotalReceived    float64          `json:"totalReceived"`
+		NetSpend         float64          `json:"netSpend"`
+		Percentage       float64          `json:"percentage"`
+		TransactionCount int              `json:"transactionCount"`
+		Currency         string           `json:"currency"`
+	} `json:"breakdown"`
+}
+
+// amount converts a float amount in major units to an Amount.
+func amount(currency string, major float64) (Amount, error) {
+	m, err := MoneyFromMajor(currency, major)
+	return m.Amount(), err
+}
+
+// insights converts the API response to SpendingInsights.
+func (si *spendingInsights) insights() (*SpendingInsights, error) {
+	var (
+		ins SpendingInsights
+		err error
+	)
+	ins.Period = si.Period
+	if ins.TotalSpent, err = amount(si.Currency, si.TotalSpent); err != nil {
+		return nil, err
+	}
+	if ins.TotalReceived, err = amount(si.Currency, si.TotalReceived); err != nil {
+		return nil, err
+	}
+	if ins.NetSpend, err = amount(si.Currency, si.NetSpend); err != nil {
+		return nil, err
+	}
+
+	ins.Breakdown = make([]SpendingBreakdown, len(si.Breakdown))
+	for i, b := range si.Breakdown {
+		cur := b.Currency
+		if cur == "" {
+			cur = si.Currency
+		}
+		sb := SpendingBreakdown{
+			CounterPartyUID:  b.CounterPartyUID,
+			CounterPartyType: b.CounterPartyType,
+			CounterPartyName: b.CounterPartyName,
+			SpendingCategory: b.SpendingCategory,
+			CountryCode:      b.CountryCode,
+			MerchantUID:      b.MerchantUID,
+			MerchantName:     b.MerchantName,
+			Percentage:       b.Percentage,
+			TransactionCount: b.TransactionCount,
+		}
+		if sb.TotalSpent, err = amount(cur, b.TotalSpent); err != nil {
+			return nil, err
+		}
+		if sb.TotalReceived, err = amount(cur, b.TotalReceived); err != nil {
+			return nil, err
+		}
+		if sb.NetSpend, err = amount(cur, b.NetSpend); err != nil {
+			return nil, err
+		}
+		ins.Breakdown[i] = sb
+	}
+	return &ins, nil
+}
+
+// insightsPaths holds the path of the spending insights endpoint for each grouping.
+var insightsPaths = map[SpendingGrouping]string{
+	ByCounterParty: "counter-party",
+	ByCategory:     "spending-category",
+	ByCountry:      "country",
+	ByMerchant:     "merchant",
+}
+
+// SpendingInsights returns the spending on an account during a month broken down as requested. It
+// also returns the http response in case this is required for further processing. An error will
+// be returned if the grouping is not recognised or if unable to retrieve the insights from the API.
+func (c *Client) SpendingInsights(ctx context.Context, act string, by SpendingGrouping, year int, month time.Month) (*SpendingInsights, *http.Response, error) {
+	path, ok := insightsPaths[by]
+	if !ok {
+		return nil, nil, errors.Errorf("unknown spending grouping %d", by)
+	}
+
+	req, err := c.NewRequest("GET", "/api/v2/accounts/"+act+"/spending-insights/"+path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("year", strconv.Itoa(year))
+	q.Add("month", strings.ToUpper(month.String()))
+	req.URL.RawQuery = q.Encode()
+
+	var si spendingInsights
+	resp, err := c.Do(ctx, req, &si)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	ins, err := si.insights()
+	return ins, resp, err
+}
+
+// SpendingByCounterParty returns the spending on an account during a month broken down by
+// counterparty.
+func (c *Client) SpendingByCounterParty(ctx context.Context, act string, year int, month time.Month) (*SpendingInsights, *http.Response, error) {
+	return c.SpendingInsights(ctx, act, ByCounterParty, year, month)
+}
+
+// SpendingByCategory returns the spending on an account during a month broken down by spending
+// category.
+func (c *Client) SpendingByCategory(ctx context.Context, act string, year int, month time.Month) (*SpendingInsights, *http.Response, error) {
+	return c.SpendingInsights(ctx, act, ByCategory, year, month)
+}
+
+// SpendingByCountry returns the spending on an account during a month broken down by country.
+func (c *Client) SpendingByCountry(ctx context.Context, act string, year int, month time.Month) (*SpendingInsights, *http.Response, error) {
+	return c.SpendingInsights(ctx, act, ByCountry, year, month)
+}
+
+// SpendingByMerchant returns the spending on an account during a month broken down by merchant.
+func (c *Client) SpendingByMerchant(ctx context.Context, act string, year int, month time.Month) (*SpendingInsights, *http.Response, error) {
+	return c.SpendingInsights(ctx, act, ByMerchant, year, month)
+}
+
+// ComputeSpendingInsights computes the same breakdown as SpendingInsights from feed items, such as
+// those returned by FeedBetween, so that insights can be produced for any period. Declined and
+// reversed items and transfers between the categories of an account are not counted, and when
+// grouping by merchant only card payments to merchants are. An error is returned if the items are
+// in more than one currency.
+func ComputeSpendingInsights(items []FeedItem, by SpendingGrouping) (*SpendingInsights, error) {
+	var (
+		cur    string
+		groups = make(map[string]*SpendingBreakdown)
+		order  []string
+	)
+	for _, it := range items {
+		if it.Status == "DECLINED" || it.Status == "REVERSED" || it.CounterPartyType == "CATEGORY" {
+			continue
+		}
+		if by == ByMerchant && it.CounterPartyType != "MERCHANT" {
+			continue
+		}
+		if cur == "" {
+			cur = it.Amount.Currency
+		}
+
+		key, b := spendingKey(it, by)
+		g, ok := groups[key]
+		if !ok {
+			b.TotalSpent = Amount{Currency: cur}
+			b.TotalReceived = Amount{Currency: cur}
+			g = &b
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		total := &g.TotalSpent
+		if it.Direction == "IN" {
+			total = &g.TotalReceived
+		}
+		sum, err := total.Money().Add(it.Amount.Money())
+		if err != nil {
+			return nil, err
+		}
+		*total = sum.Amount()
+		g.TransactionCount++
+	}
+
+	ins := &SpendingInsights{
+		TotalSpent:    Amount{Currency: cur},
+		TotalReceived: Amount{Currency: cur},
+		Breakdown:     make([]SpendingBreakdown, 0, len(order)),
+	}
+	for _, key := range order {
+		g := groups[key]
+		g.NetSpend = Amount{Currency: cur, MinorUnits: g.TotalSpent.MinorUnits - g.TotalReceived.MinorUnits}
+		ins.TotalSpent.MinorUnits += g.TotalSpent.MinorUnits
+		ins.TotalReceived.MinorUnits += g.TotalReceived.MinorUnits
+		ins.Breakdown = append(ins.Breakdown, *g)
+	}
+	ins.NetSpend = Amount{Currency: cur, MinorUnits: ins.TotalSpent.MinorUnits - ins.TotalReceived.MinorUnits}
+
+	for i := range ins.Breakdown {
+		if ins.TotalSpent.MinorUnits > 0 {
+			ins.Breakdown[i].Percentage = float64(ins.Breakdown[i].TotalSpent.MinorUnits) * 100 / float64(ins.TotalSpent.MinorUnits)
+		}
+	}
+	sort.SliceStable(ins.Breakdown, func(i, j int) bool {
+		return ins.Breakdown[i].NetSpend.MinorUnits > ins.Breakdown[j].NetSpend.MinorUnits
+	})
+	return ins, nil
+}
+
+// spendingKey returns the key an item is grouped under along with a breakdown identifying the
+// group.
+func spendingKey(it FeedItem, by SpendingGrouping) (string, SpendingBreakdown) {
+	switch by {
+	case ByCategory:
+		return string(it.SpendingCategory), SpendingBreakdown{SpendingCategory: it.SpendingCategory}
+	case ByCountry:
+		return it.Country, SpendingBreakdown{CountryCode: it.Country}
+	case ByMerchant:
+		return it.CounterPartyUID, SpendingBreakdown{MerchantUID: it.CounterPartyUID, MerchantName: it.CounterPartyName}
+	}
+
+	key := it.CounterPartyUID
+	if key == "" {
+		key = it.CounterPartyName
+	}
+	return key, SpendingBreakdown{
+		CounterPartyUID:  it.CounterPartyUID,
+		CounterPartyType: it.CounterPartyType,
+		CounterPartyName: it.CounterPartyName,
+	}
+}
@@ -0,0 +1,143 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestSpendingInsights(t *testing.T) {
+	cases := []struct {
+		name string
+		path string
+		call func(c *Client) (*SpendingInsights, *http.Response, error)
+	}{
+		{"counterparty", "counter-party", func(c *Client) (*SpendingInsights, *http.Response, error) {
+			return c.SpendingByCounterParty(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", 2020, time.June)
+		}},
+		{"category", "spending-category", func(c *Client) (*SpendingInsights, *http.Response, error) {
+			return c.SpendingByCategory(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", 2020, time.June)
+		}},
+		{"country", "country", func(c *Client) (*SpendingInsights, *http.Response, error) {
+			return c.SpendingByCountry(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", 2020, time.June)
+		}},
+		{"merchant", "merchant", func(c *Client) (*SpendingInsights, *http.Response, error) {
+			return c.SpendingByMerchant(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", 2020, time.June)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(st *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			mux.HandleFunc("/api/v2/accounts/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/spending-insights/"+tc.path, func(w http.ResponseWriter, r *http.Request) {
+				checkMethod(st, r, http.MethodGet)
+				if q := r.URL.Query(); q.Get("year") != "2020" || q.Get("month") != "JUNE" {
+					st.Error("should request the given month", cross, r.URL.RawQuery)
+				}
+				fmt.Fprint(w, `{
+					"period": "2020-06",
+					"totalSpent": 123.45,
+					"totalReceived": 2500,
+					"netSpend": -2376.55,
+					"currency": "GBP",
+					"breakdown": [
+						{
+							"spendingCategory": "GROCERIES",
+							"totalSpent": 100.1,
+							"totalReceived": 0,
+							"netSpend": 100.1,
+							"percentage": 81.09,
+							"transactionCount": 4,
+							"currency": "GBP"
+						}
+					]
+				}`)
+			})
+
+			got, _, err := tc.call(client)
+			checkNoError(st, err)
+
+			if got == nil || got.Period != "2020-06" || got.TotalSpent != (Amount{Currency: "GBP", MinorUnits: 12345}) || got.NetSpend.MinorUnits != -237655 {
+				st.Fatal("should return the totals as exact amounts", cross, got)
+			}
+			if len(got.Breakdown) != 1 || got.Breakdown[0].TotalSpent.MinorUnits != 10010 || got.Breakdown[0].SpendingCategory != SpendingCategoryGroceries {
+				st.Error("should return the breakdown", cross, got.Breakdown)
+			}
+		})
+	}
+}
+
+func TestSpendingInsightsUnknownGrouping(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not send a request for an unknown grouping", cross, r.URL.Path)
+	})
+
+	_, resp, err := client.SpendingInsights(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", SpendingGrouping(99), 2020, time.June)
+	checkHasError(t, err)
+	if resp != nil {
+		t.Error("should not return a response", cross, resp)
+	}
+}
+
+var insightItems = []FeedItem{
+	{Amount: Amount{"GBP", 1250}, Direction: "OUT", Status: "SETTLED", CounterPartyType: "MERCHANT", CounterPartyUID: "m1", CounterPartyName: "Grocer", SpendingCategory: SpendingCategoryGroceries, Country: "GB"},
+	{Amount: Amount{"GBP", 750}, Direction: "OUT", Status: "SETTLED", CounterPartyType: "MERCHANT", CounterPartyUID: "m1", CounterPartyName: "Grocer", SpendingCategory: SpendingCategoryGroceries, Country: "GB"},
+	{Amount: Amount{"GBP", 500}, Direction: "IN", Status: "SETTLED", CounterPartyType: "MERCHANT", CounterPartyUID: "m1", CounterPartyName: "Grocer", SpendingCategory: SpendingCategoryGroceries, Country: "GB"},
+	{Amount: Amount{"GBP", 2000}, Direction: "OUT", Status: "PENDING", CounterPartyType: "MERCHANT", CounterPartyUID: "m2", CounterPartyName: "Cafe", SpendingCategory: SpendingCategoryEatingOut, Country: "FR"},
+	{Amount: Amount{"GBP", 9999}, Direction: "OUT", Status: "DECLINED", CounterPartyType: "MERCHANT", CounterPartyUID: "m2", CounterPartyName: "Cafe", SpendingCategory: SpendingCategoryEatingOut, Country: "FR"},
+	{Amount: Amount{"GBP", 5000}, Direction: "OUT", Status: "SETTLED", CounterPartyType: "CATEGORY", CounterPartyUID: "goal", CounterPartyName: "Holiday", SpendingCategory: SpendingCategorySaving},
+	{Amount: Amount{"GBP", 100000}, Direction: "IN", Status: "SETTLED", CounterPartyType: "SENDER", CounterPartyUID: "p1", CounterPartyName: "Employer", SpendingCategory: SpendingCategoryIncome, Country: "GB"},
+}
+
+func TestComputeSpendingInsights(t *testing.T) {
+	ins, err := ComputeSpendingInsights(insightItems, ByCategory)
+	checkNoError(t, err)
+
+	if ins.TotalSpent.MinorUnits != 4000 || ins.TotalReceived.MinorUnits != 100500 || ins.NetSpend.MinorUnits != -96500 {
+		t.Error("should total the spending, ignoring declined items and transfers", cross, ins)
+	}
+	if len(ins.Breakdown) != 3 {
+		t.Fatal("should break down spending by category", cross, ins.Breakdown)
+	}
+
+	first := ins.Breakdown[0]
+	if first.SpendingCategory != SpendingCategoryEatingOut || first.NetSpend.MinorUnits != 2000 || first.Percentage != 50 {
+		t.Error("should order the breakdown by net spend", cross, first)
+	}
+	groceries := ins.Breakdown[1]
+	if groceries.TotalSpent.MinorUnits != 2000 || groceries.TotalReceived.MinorUnits != 500 || groceries.TransactionCount != 3 {
+		t.Error("should total each category", cross, groceries)
+	}
+
+	ins, err = ComputeSpendingInsights(insightItems, ByMerchant)
+	checkNoError(t, err)
+	if len(ins.Breakdown) != 2 || ins.Breakdown[0].MerchantName != "Cafe" {
+		t.Error("should only count merchants when grouping by merchant", cross, ins.Breakdown)
+	}
+
+	ins, err = ComputeSpendingInsights(insightItems, ByCountry)
+	checkNoError(t, err)
+	if len(ins.Breakdown) != 2 {
+		t.Error("should break down spending by country", cross, ins.Breakdown)
+	}
+}
+
+func TestComputeSpendingInsightsMixedCurrencies(t *testing.T) {
+	items := []FeedItem{
+		{Amount: Amount{"GBP", 100}, Direction: "OUT", CounterPartyName: "A"},
+		{Amount: Amount{"EUR", 100}, Direction: "OUT", CounterPartyName: "A"},
+	}
+	_, err := ComputeSpendingInsights(items, ByCounterParty)
+	if !errors.Is(err, ErrCurrencyMismatch) {
+		t.Error("should refuse items in different currencies", cross, err)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Package atomicfile replaces files so that, even after a crash or power loss,
+readers see either the old contents or the new contents in full.
+*/
+package atomicfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// WriteFile writes data to a temporary file beside path, flushes it to disk and
+// renames it over path, then syncs the directory so that the rename is durable.
+// The file is readable only by the current user.
+func WriteFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+	return SyncDir(dir)
+}
+
+// SyncDir flushes a directory to disk so that files created, renamed or removed
+// within it are durable. It does nothing on Windows, where directories cannot be
+// synced.
+func SyncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
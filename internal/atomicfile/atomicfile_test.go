@@ -0,0 +1,44 @@
+package atomicfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const cross = "✗"
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "data.json")
+	for _, want := range []string{`{"a":1}`, `{"b":2}`} {
+		if err := WriteFile(path, []byte(want)); err != nil {
+			t.Fatal("should write the file", cross, err)
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil || string(got) != want {
+			t.Error("should replace the contents of the file", cross, string(got), err)
+		}
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Error("should not leave temporary files behind", cross, len(files))
+	}
+	if runtime.GOOS != "windows" && files[0].Mode().Perm() != 0600 {
+		t.Error("should make the file readable only by the current user", cross, files[0].Mode())
+	}
+}
+
+func TestWriteFileMissingDir(t *testing.T) {
+	if err := WriteFile(filepath.Join(os.TempDir(), "atomicfile-missing", "data.json"), nil); err == nil {
+		t.Error("should return an error when the directory does not exist", cross)
+	}
+}
@@ -0,0 +1,362 @@
+package starling
+
+// MCCCategory is the coarse grouping of a merchant category code defined by
+// the range the code falls in
+type MCCCategory string
+
+// Merchant category code groupings
+const (
+	MCCAgricultural         MCCCategory = "AGRICULTURAL_SERVICES"
+	MCCContractors          MCCCategory = "CONTRACTED_SERVICES"
+	MCCAirlines             MCCCategory = "AIRLINES"
+	MCCCarRental            MCCCategory = "CAR_RENTAL"
+	MCCLodging              MCCCategory = "LODGING"
+	MCCTransportation       MCCCategory = "TRANSPORTATION_SERVICES"
+	MCCUtilities            MCCCategory = "UTILITY_SERVICES"
+	MCCRetail               MCCCategory = "RETAIL_OUTLET_SERVICES"
+	MCCClothing             MCCCategory = "CLOTHING_STORES"
+	MCCMiscStores           MCCCategory = "MISCELLANEOUS_STORES"
+	MCCBusinessServices     MCCCategory = "BUSINESS_SERVICES"
+	MCCProfessionalServices MCCCategory = "PROFESSIONAL_SERVICES"
+	MCCGovernment           MCCCategory = "GOVERNMENT_SERVICES"
+)
+
+// MerchantCategoryCode describes an ISO 18245 merchant category code
+type MerchantCategoryCode struct {
+	Code        int32
+	Description string
+	Category    MCCCategory
+}
+
+// mccRanges maps ranges of codes to their grouping. Codes in the airline, car
+// rental and lodging ranges identify individual companies and are described by
+// the range.
+var mccRanges = []struct {
+	from, to    int32
+	category    MCCCategory
+	description string
+}{
+	{1, 1499, MCCAgricultural, ""},
+	{1500, 2999, MCCContractors, ""},
+	{3000, 3350, MCCAirlines, "Airlines and air carriers"},
+	{3351, 3500, MCCCarRental, "Car rental agencies"},
+	{3501, 3999, MCCLodging, "Hotels, motels and resorts"},
+	{4000, 4799, MCCTransportation, ""},
+	{4800, 4999, MCCUtilities, ""},
+	{5000, 5599, MCCRetail, ""},
+	{5600, 5699, MCCClothing, ""},
+	{5700, 7299, MCCMiscStores, ""},
+	{7300, 7999, MCCBusinessServices, ""},
+	{8000, 8999, MCCProfessionalServices, ""},
+	{9000, 9999, MCCGovernment, ""},
+}
+
+// mccDescriptions holds the descriptions of ISO 18245 merchant category codes.
+var mccDescriptions = map[int32]string{
+	742:  "Veterinary services",
+	763:  "Agricultural cooperatives",
+	780:  "Landscaping and horticultural services",
+	1520: "General contractors, residential and commercial",
+	1711: "Heating, plumbing and air conditioning contractors",
+	1731: "Electrical contractors",
+	1740: "Masonry, stonework, tile setting, plastering and insulation contractors",
+	1750: "Carpentry contractors",
+	1761: "Roofing, siding and sheet metal work contractors",
+	1771: "Concrete work contractors",
+	1799: "Special trade contractors",
+	2741: "Miscellaneous publishing and printing",
+	2791: "Typesetting, platemaking and related services",
+	2842: "Speciality cleaning, polishing and sanitation preparations",
+	4011: "Railroads",
+	4111: "Local and suburban commuter passenger transportation",
+	4112: "Passenger railways",
+	4119: "Ambulance services",
+	4121: "Taxicabs and limousines",
+	4131: "Bus lines",
+	4214: "Motor freight carriers and trucking",
+	4215: "Courier services",
+	4225: "Public warehousing and storage",
+	4411: "Steamship and cruise lines",
+	4457: "Boat rentals and leasing",
+	4468: "Marinas, marine service and supplies",
+	4511: "Airlines and air carriers",
+	4582: "Airports, flying fields and airport terminals",
+	4722: "Travel agencies and tour operators",
+	4784: "Tolls and bridge fees",
+	4789: "Transportation services",
+	4812: "Telecommunication equipment and telephone sales",
+	4814: "Telecommunication services",
+	4816: "Computer network and information services",
+	4821: "Telegraph services",
+	4829: "Wire transfers and money orders",
+	4899: "Cable, satellite and other pay television and radio services",
+	4900: "Utilities: electric, gas, water and sanitary",
+	5013: "Motor vehicle supplies and new parts",
+	5021: "Office and commercial furniture",
+	5039: "Construction materials",
+	5044: "Photographic, photocopy and microfilm equipment",
+	5045: "Computers and peripheral equipment",
+	5046: "Commercial equipment",
+	5047: "Medical, dental, ophthalmic and hospital equipment",
+	5051: "Metal service centres",
+	5065: "Electrical parts and equipment",
+	5072: "Hardware, equipment and supplies",
+	5074: "Plumbing and heating equipment",
+	5085: "Industrial supplies",
+	5094: "Precious stones and metals, watches and jewellery",
+	5099: "Durable goods",
+	5111: "Stationery, office supplies and printing paper",
+	5122: "Drugs, drug proprietaries and druggist sundries",
+	5131: "Piece goods, notions and other dry goods",
+	5137: "Uniforms and commercial clothing",
+	5139: "Commercial footwear",
+	5169: "Chemicals and allied products",
+	5172: "Petroleum and petroleum products",
+	5192: "Books, periodicals and newspapers",
+	5193: "Florists' supplies, nursery stock and flowers",
+	5198: "Paints, varnishes and supplies",
+	5199: "Nondurable goods",
+	5200: "Home supply warehouse stores",
+	5211: "Lumber and building materials stores",
+	5231: "Glass, paint and wallpaper stores",
+	5251: "Hardware stores",
+	5261: "Nurseries and lawn and garden supply stores",
+	5271: "Mobile home dealers",
+	5300: "Wholesale clubs",
+	5309: "Duty free stores",
+	5310: "Discount stores",
+	5311: "Department stores",
+	5331: "Variety stores",
+	5399: "Miscellaneous general merchandise",
+	5411: "Grocery stores and supermarkets",
+	5422: "Freezer and locker meat provisioners",
+	5441: "Candy, nut and confectionery stores",
+	5451: "Dairy products stores",
+	5462: "Bakeries",
+	5499: "Miscellaneous food stores",
+	5511: "Car and truck dealers, new and used",
+	5521: "Car and truck dealers, used only",
+	5531: "Auto and home supply stores",
+	5532: "Automotive tyre stores",
+	5533: "Automotive parts and accessories stores",
+	5541: "Service stations",
+	5542: "Automated fuel dispensers",
+	5551: "Boat dealers",
+	5561: "Camper, recreational and utility trailer dealers",
+	5571: "Motorcycle shops and dealers",
+	5592: "Motor home dealers",
+	5598: "Snowmobile dealers",
+	5599: "Miscellaneous automotive, aircraft and farm equipment dealers",
+	5611: "Men's and boys' clothing and accessories stores",
+	5621: "Women's ready-to-wear stores",
+	5631: "Women's accessory and speciality shops",
+	5641: "Children's and infants' wear stores",
+	5651: "Family clothing stores",
+	5655: "Sports and riding apparel stores",
+	5661: "Shoe stores",
+	5681: "Furriers and fur shops",
+	5691: "Men's and women's clothing stores",
+	5697: "Tailors and alterations",
+	5698: "Wig and toupee stores",
+	5699: "Miscellaneous apparel and accessory shops",
+	5712: "Furniture, home furnishings and equipment stores",
+	5713: "Floor covering stores",
+	5714: "Drapery, window covering and upholstery stores",
+	5718: "Fireplace and accessories stores",
+	5719: "Miscellaneous home furnishing speciality stores",
+	5722: "Household appliance stores",
+	5732: "Electronics stores",
+	5733: "Music stores, musical instruments and sheet music",
+	5734: "Computer software stores",
+	5735: "Record stores",
+	5811: "Caterers",
+	5812: "Eating places and restaurants",
+	5813: "Drinking places, bars and nightclubs",
+	5814: "Fast food restaurants",
+	5815: "Digital goods: media, books, films and music",
+	5816: "Digital goods: games",
+	5817: "Digital goods: applications",
+	5818: "Digital goods: large digital goods merchant",
+	5912: "Drug stores and pharmacies",
+	5921: "Package stores: beer, wine and liquor",
+	5931: "Used merchandise and second-hand stores",
+	5932: "Antique shops",
+	5933: "Pawn shops",
+	5935: "Wrecking and salvage yards",
+	5937: "Antique reproductions",
+	5940: "Bicycle shops",
+	5941: "Sporting goods stores",
+	5942: "Book stores",
+	5943: "Stationery, office and school supply stores",
+	5944: "Jewellery, watch, clock and silverware stores",
+	5945: "Hobby, toy and game shops",
+	5946: "Camera and photographic supply stores",
+	5947: "Gift, card, novelty and souvenir shops",
+	5948: "Luggage and leather goods stores",
+	5949: "Sewing, needlework, fabric and piece goods stores",
+	5950: "Glassware and crystal stores",
+	5960: "Direct marketing: insurance services",
+	5962: "Direct marketing: travel related arrangement services",
+	5963: "Door-to-door sales",
+	5964: "Direct marketing: catalogue merchants",
+	5965: "Direct marketing: combination catalogue and retail merchants",
+	5966: "Direct marketing: outbound telemarketing",
+	5967: "Direct marketing: inbound telemarketing",
+	5968: "Direct marketing: continuity and subscription merchants",
+	5969: "Direct marketing: other",
+	5970: "Artists' supply and craft shops",
+	5971: "Art dealers and galleries",
+	5972: "Stamp and coin stores",
+	5973: "Religious goods stores",
+	5975: "Hearing aids: sales, service and supplies",
+	5976: "Orthopaedic goods and prosthetic devices",
+	5977: "Cosmetic stores",
+	5978: "Typewriter stores",
+	5983: "Fuel dealers: fuel oil, wood, coal and liquefied petroleum",
+	5992: "Florists",
+	5993: "Cigar stores and stands",
+	5994: "News dealers and newsstands",
+	5995: "Pet shops, pet food and supplies",
+	5996: "Swimming pools: sales and supplies",
+	5997: "Electric razor stores",
+	5998: "Tent and awning shops",
+	5999: "Miscellaneous and speciality retail stores",
+	6010: "Financial institutions: manual cash disbursements",
+	6011: "Financial institutions: automated cash disbursements",
+	6012: "Financial institutions: merchandise and services",
+	6051: "Non-financial institutions: foreign currency, money orders and travellers' cheques",
+	6211: "Security brokers and dealers",
+	6300: "Insurance sales, underwriting and premiums",
+	6513: "Real estate agents and managers: rentals",
+	6540: "Stored value card purchase and load",
+	7011: "Hotels, motels and resorts",
+	7012: "Timeshares",
+	7032: "Sporting and recreational camps",
+	7033: "Trailer parks and campgrounds",
+	7210: "Laundry, cleaning and garment services",
+	7211: "Laundries",
+	7216: "Dry cleaners",
+	7217: "Carpet and upholstery cleaning",
+	7221: "Photographic studios",
+	7230: "Beauty and barber shops",
+	7251: "Shoe repair and hat cleaning",
+	7261: "Funeral services and crematoria",
+	7273: "Dating and escort services",
+	7276: "Tax preparation services",
+	7277: "Counselling services",
+	7278: "Buying and shopping services",
+	7296: "Clothing rental",
+	7297: "Massage parlours",
+	7298: "Health and beauty spas",
+	7299: "Miscellaneous personal services",
+	7311: "Advertising services",
+	7321: "Consumer credit reporting agencies",
+	7333: "Commercial photography, art and graphics",
+	7338: "Quick copy, reproduction and blueprinting services",
+	7339: "Stenographic and secretarial support services",
+	7342: "Exterminating and disinfecting services",
+	7349: "Cleaning, maintenance and janitorial services",
+	7361: "Employment agencies and temporary help services",
+	7372: "Computer programming, data processing and integrated systems design services",
+	7375: "Information retrieval services",
+	7379: "Computer maintenance and repair services",
+	7392: "Management, consulting and public relations services",
+	7393: "Detective, protective and security services",
+	7394: "Equipment, tool, furniture and appliance rental and leasing",
+	7395: "Photofinishing laboratories and photo developing",
+	7399: "Business services",
+	7511: "Truck stops",
+	7512: "Car rental agencies",
+	7513: "Truck and utility trailer rentals",
+	7519: "Motor home and recreational vehicle rentals",
+	7523: "Parking lots and garages",
+	7531: "Automotive body repair shops",
+	7534: "Tyre retreading and repair shops",
+	7535: "Automotive paint shops",
+	7538: "Automotive service shops",
+	7542: "Car washes",
+	7549: "Towing services",
+	7622: "Electronics repair shops",
+	7623: "Air conditioning and refrigeration repair shops",
+	7629: "Electrical and small appliance repair shops",
+	7631: "Watch, clock and jewellery repair shops",
+	7641: "Furniture reupholstery, repair and refinishing",
+	7692: "Welding services",
+	7699: "Miscellaneous repair shops and related services",
+	7800: "Government owned lotteries",
+	7801: "Government licensed online casinos",
+	7802: "Government licensed horse and dog racing",
+	7829: "Motion picture and video tape production and distribution",
+	7832: "Motion picture theatres",
+	7841: "Video tape rental stores",
+	7911: "Dance halls, studios and schools",
+	7922: "Theatrical producers and ticket agencies",
+	7929: "Bands, orchestras and miscellaneous entertainers",
+	7932: "Billiard and pool establishments",
+	7933: "Bowling alleys",
+	7941: "Commercial sports, professional sports clubs and sports promoters",
+	7991: "Tourist attractions and exhibits",
+	7992: "Public golf courses",
+	7993: "Video amusement game supplies",
+	7994: "Video game arcades and establishments",
+	7995: "Betting, including lottery tickets, casino gaming chips and off-track betting",
+	7996: "Amusement parks, circuses, carnivals and fortune tellers",
+	7997: "Membership clubs, country clubs and private golf courses",
+	7998: "Aquariums, seaquariums and dolphinariums",
+	7999: "Recreation services",
+	8011: "Doctors and physicians",
+	8021: "Dentists and orthodontists",
+	8031: "Osteopaths",
+	8041: "Chiropractors",
+	8042: "Optometrists and ophthalmologists",
+	8043: "Opticians, optical goods and eyeglasses",
+	8049: "Podiatrists and chiropodists",
+	8050: "Nursing and personal care facilities",
+	8062: "Hospitals",
+	8071: "Medical and dental laboratories",
+	8099: "Medical services and health practitioners",
+	8111: "Legal services and attorneys",
+	8211: "Elementary and secondary schools",
+	8220: "Colleges, universities and professional schools",
+	8241: "Correspondence schools",
+	8244: "Business and secretarial schools",
+	8249: "Trade and vocational schools",
+	8299: "Schools and educational services",
+	8351: "Child care services",
+	8398: "Charitable and social service organisations",
+	8641: "Civic, social and fraternal associations",
+	8651: "Political organisations",
+	8661: "Religious organisations",
+	8675: "Automobile associations",
+	8699: "Membership organisations",
+	8734: "Testing laboratories",
+	8911: "Architectural, engineering and surveying services",
+	8931: "Accounting, auditing and bookkeeping services",
+	8999: "Professional services",
+	9211: "Court costs, including alimony and child support",
+	9222: "Fines",
+	9223: "Bail and bond payments",
+	9311: "Tax payments",
+	9399: "Government services",
+	9402: "Postal services",
+	9405: "Government agencies and departments",
+	9950: "Intra-company purchases",
+}
+
+// LookupMCC returns the description and grouping of a merchant category code.
+// It returns false if the code is outside the ranges defined by ISO 18245.
+// Codes within a range but without a description of their own are returned
+// with an empty Description.
+func LookupMCC(code int32) (MerchantCategoryCode, bool) {
+	for _, r := range mccRanges {
+		if code < r.from || code > r.to {
+			continue
+		}
+		mcc := MerchantCategoryCode{Code: code, Description: mccDescriptions[code], Category: r.category}
+		if mcc.Description == "" {
+			mcc.Description = r.description
+		}
+		return mcc, true
+	}
+	return MerchantCategoryCode{}, false
+}
@@ -0,0 +1,28 @@
+package starling
+
+import "testing"
+
+func TestLookupMCC(t *testing.T) {
+	cases := []struct {
+		code        int32
+		ok          bool
+		description string
+		category    MCCCategory
+	}{
+		{5411, true, "Grocery stores and supermarkets", MCCRetail},
+		{5812, true, "Eating places and restaurants", MCCMiscStores},
+		{3005, true, "Airlines and air carriers", MCCAirlines},
+		{3640, true, "Hotels, motels and resorts", MCCLodging},
+		{9311, true, "Tax payments", MCCGovernment},
+		{4998, true, "", MCCUtilities},
+		{0, false, "", ""},
+		{10000, false, "", ""},
+	}
+
+	for _, tc := range cases {
+		got, ok := LookupMCC(tc.code)
+		if ok != tc.ok || got.Description != tc.description || got.Category != tc.category {
+			t.Error("should describe MCC", tc.code, cross, got, ok)
+		}
+	}
+}
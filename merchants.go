@@ -0,0 +1,215 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Merchant is a business that accepts card payments
+type Merchant struct {
+	UID             string `json:"merchantUid"`
+	Name            string `json:"name"`
+	Website         string `json:"website"`
+	PhoneNumber     string `json:"phoneNumber"`
+	TwitterUsername string `json:"twitterUsername"`
+}
+
+// MerchantOutlet is a location at which a merchant accepts card payments, such as a shop
+type MerchantOutlet struct {
+	MerchantUID   string `json:"merchantUid"`
+	UID           string `json:"merchantLocationUid"`
+	MerchantName  string `json:"merchantName"`
+	LocationName  string `json:"locationName"`
+	Address       string `json:"address"`
+	PhoneNumber   string `json:"phoneNumber"`
+	GooglePlaceID string `json:"googlePlaceId"`
+	MCC           int32  `json:"mastercardMerchantCategoryCode"`
+}
+
+// Merchant returns the merchant with the given UID, which is the CounterPartyUID of feed items
+// paid to merchants. It also returns the http response in case this is required for further
+// processing. An error will be returned if unable to retrieve the merchant from the API.
+func (c *Client) Merchant(ctx context.Context, uid string) (*Merchant, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/merchants/"+uid, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var m *Merchant
+	resp, err := c.Do(ctx, req, &m)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return m, resp, nil
+}
+
+// MerchantOutlet returns an outlet of a merchant, which is the CounterPartySubEntityUID of feed
+// items paid to merchants. It also returns the http response in case this is required for further
+// processing. An error will be returned if unable to retrieve the outlet from the API.
+func (c *Client) MerchantOutlet(ctx context.Context, merchantUID, outletUID string) (*MerchantOutlet, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/merchants/"+merchantUID+"/outlets/"+outletUID, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var o *MerchantOutlet
+	resp, err := c.Do(ctx, req, &o)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return o, resp, nil
+}
+
+// EnrichedFeedItem is a feed item decorated with details of the merchant it was paid to. The
+// merchant, outlet and MCC are nil if the item was not paid to a merchant or the details are not
+// known.
+type EnrichedFeedItem struct {
+	FeedItem
+	Merchant *Merchant
+	Outlet   *MerchantOutlet
+	MCC      *MerchantCategoryCode
+}
+
+// MerchantName returns the name of the merchant, falling back to the name of the counterparty.
+func (e EnrichedFeedItem) MerchantName() string {
+	if e.Merchant != nil && e.Merchant.Name != "" {
+		return e.Merchant.Name
+	}
+	return e.CounterPartyName
+}
+
+// Location returns the name and address of the outlet, or an empty string if not known.
+func (e EnrichedFeedItem) Location() string {
+	if e.Outlet == nil {
+		return ""
+	}
+	switch {
+	case e.Outlet.LocationName == "":
+		return e.Outlet.Address
+	case e.Outlet.Address == "":
+		return e.Outlet.LocationName
+	}
+	return e.Outlet.LocationName + ", " + e.Outlet.Address
+}
+
+// Enricher decorates feed items with merchant details. Merchants and outlets are looked up once
+// and cached in memory for the life of the Enricher, including those the API does not know. It is
+// safe for concurrent use.
+type Enricher struct {
+	client *Client
+
+	mu        sync.Mutex
+	merchants map[string]*Merchant
+	outlets   map[string]*MerchantOutlet
+}
+
+// NewEnricher returns an Enricher that looks up merchants using the given client.
+func NewEnricher(c *Client) *Enricher {
+	return &Enricher{
+		client:    c,
+		merchants: make(map[string]*Merchant),
+		outlets:   make(map[string]*MerchantOutlet),
+	}
+}
+
+// Enrich decorates feed items with details of the merchants they were paid to. Items that were
+// not paid to a merchant are returned undecorated. An error will be returned if unable to
+// retrieve a merchant or outlet from the API for any reason other than it not being found.
+func (e *Enricher) Enrich(ctx context.Context, items []FeedItem) ([]EnrichedFeedItem, error) {
+	enriched := make([]EnrichedFeedItem, len(items))
+	for i, it := range items {
+		var err error
+		if enriched[i], err = e.enrich(ctx, it, 0); err != nil {
+			return nil, err
+		}
+	}
+	return enriched, nil
+}
+
+// EnrichWebhook decorates feed items received by webhook in the same way as Enrich. Card
+// payments are given the MCC from their MasterCardFeedDetails when the merchant outlet does not
+// provide one.
+func (e *Enricher) EnrichWebhook(ctx context.Context, items []WebHookFeedItem) ([]EnrichedFeedItem, error) {
+	enriched := make([]EnrichedFeedItem, len(items))
+	for i, it := range items {
+		var err error
+		if enriched[i], err = e.enrich(ctx, it.FeedItem, it.MasterCardFeedDetails.MCC); err != nil {
+			return nil, err
+		}
+	}
+	return enriched, nil
+}
+
+// enrich decorates a single feed item, falling back to mcc if the outlet does not provide one.
+func (e *Enricher) enrich(ctx context.Context, it FeedItem, mcc int32) (EnrichedFeedItem, error) {
+	enriched := EnrichedFeedItem{FeedItem: it}
+	if it.CounterPartyType == "MERCHANT" && it.CounterPartyUID != "" {
+		m, err := e.merchant(ctx, it.CounterPartyUID)
+		if err != nil {
+			return enriched, err
+		}
+		enriched.Merchant = m
+
+		if it.CounterPartySubEntityUID != "" {
+			o, err := e.outlet(ctx, it.CounterPartyUID, it.CounterPartySubEntityUID)
+			if err != nil {
+				return enriched, err
+			}
+			enriched.Outlet = o
+		}
+	}
+
+	if enriched.Outlet != nil && enriched.Outlet.MCC != 0 {
+		mcc = enriched.Outlet.MCC
+	}
+	if code, ok := LookupMCC(mcc); ok {
+		enriched.MCC = &code
+	}
+	return enriched, nil
+}
+
+// merchant returns a merchant from the cache, looking it up if it is not there. A merchant
+// unknown to the API is returned as nil.
+func (e *Enricher) merchant(ctx context.Context, uid string) (*Merchant, error) {
+	e.mu.Lock()
+	m, ok := e.merchants[uid]
+	e.mu.Unlock()
+	if ok {
+		return m, nil
+	}
+
+	m, _, err := e.client.Merchant(ctx, uid)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.merchants[uid] = m
+	e.mu.Unlock()
+	return m, nil
+}
+
+// outlet returns an outlet from the cache, looking it up if it is not there. An outlet unknown to
+// the API is returned as nil.
+func (e *Enricher) outlet(ctx context.Context, merchantUID, outletUID string) (*MerchantOutlet, error) {
+	key := merchantUID + "/" + outletUID
+	e.mu.Lock()
+	o, ok := e.outlets[key]
+	e.mu.Unlock()
+	if ok {
+		return o, nil
+	}
+
+	o, _, err := e.client.MerchantOutlet(ctx, merchantUID, outletUID)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.outlets[key] = o
+	e.mu.Unlock()
+	return o, nil
+}
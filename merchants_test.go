@@ -0,0 +1,158 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestMerchant(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/merchants/a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"merchantUid": "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a",
+			"name": "Pret A Manger",
+			"website": "https://www.pret.co.uk",
+			"phoneNumber": "0343 224 0000",
+			"twitterUsername": "Pret"
+		}`)
+	})
+
+	got, _, err := client.Merchant(context.Background(), "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a")
+	checkNoError(t, err)
+
+	if got == nil || got.Name != "Pret A Manger" || got.Website != "https://www.pret.co.uk" {
+		t.Error("should return the merchant", cross, got)
+	}
+}
+
+func TestMerchantOutlet(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/merchants/a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a/outlets/3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"merchantUid": "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a",
+			"merchantLocationUid": "3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e",
+			"merchantName": "Pret A Manger",
+			"locationName": "Pret A Manger Liverpool Street",
+			"address": "Liverpool Street Station, London EC2M 7PY",
+			"mastercardMerchantCategoryCode": 5814
+		}`)
+	})
+
+	got, _, err := client.MerchantOutlet(context.Background(), "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a", "3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e")
+	checkNoError(t, err)
+
+	if got == nil || got.LocationName != "Pret A Manger Liverpool Street" || got.MCC != 5814 {
+		t.Error("should return the outlet", cross, got)
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var merchantCalls, outletCalls int
+	mux.HandleFunc("/api/v2/merchants/a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a", func(w http.ResponseWriter, r *http.Request) {
+		merchantCalls++
+		fmt.Fprint(w, `{"merchantUid":"a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a","name":"Pret A Manger","website":"https://www.pret.co.uk"}`)
+	})
+	mux.HandleFunc("/api/v2/merchants/a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a/outlets/3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e", func(w http.ResponseWriter, r *http.Request) {
+		outletCalls++
+		fmt.Fprint(w, `{"locationName":"Liverpool Street","address":"London EC2M 7PY","mastercardMerchantCategoryCode":5814}`)
+	})
+	mux.HandleFunc("/api/v2/merchants/e5f4a3b2-c1d0-4e9f-8a7b-6c5d4e3f2a1b", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	items := []FeedItem{
+		{CounterPartyType: "MERCHANT", CounterPartyUID: "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a", CounterPartySubEntityUID: "3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e", CounterPartyName: "PRET"},
+		{CounterPartyType: "MERCHANT", CounterPartyUID: "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a", CounterPartySubEntityUID: "3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e", CounterPartyName: "PRET"},
+		{CounterPartyType: "MERCHANT", CounterPartyUID: "e5f4a3b2-c1d0-4e9f-8a7b-6c5d4e3f2a1b", CounterPartyName: "CORNER SHOP"},
+		{CounterPartyType: "PAYEE", CounterPartyUID: "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e", CounterPartyName: "Jane Smith"},
+	}
+
+	e := NewEnricher(client)
+	got, err := e.Enrich(context.Background(), items)
+	checkNoError(t, err)
+
+	if len(got) != len(items) {
+		t.Fatal("should return an item for each feed item", cross, len(got))
+	}
+	if got[0].MerchantName() != "Pret A Manger" || got[0].Merchant.Website != "https://www.pret.co.uk" {
+		t.Error("should decorate the item with the merchant", cross, got[0].Merchant)
+	}
+	if got[0].Location() != "Liverpool Street, London EC2M 7PY" {
+		t.Error("should decorate the item with the outlet location", cross, got[0].Location())
+	}
+	if got[0].MCC == nil || got[0].MCC.Description != "Fast food restaurants" {
+		t.Error("should decorate the item with the MCC", cross, got[0].MCC)
+	}
+	if merchantCalls != 1 || outletCalls != 1 {
+		t.Error("should cache merchant lookups", cross, merchantCalls, outletCalls)
+	}
+	if got[2].Merchant != nil || got[2].MerchantName() != "CORNER SHOP" {
+		t.Error("should leave unknown merchants undecorated", cross, got[2].Merchant)
+	}
+	if got[3].Merchant != nil || got[3].Outlet != nil || got[3].MCC != nil {
+		t.Error("should not decorate items not paid to merchants", cross, got[3])
+	}
+}
+
+func TestEnrichWebhook(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/merchants/a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"merchantUid":"a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a","name":"Pret A Manger"}`)
+	})
+	mux.HandleFunc("/api/v2/merchants/a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a/outlets/3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"locationName":"Liverpool Street","mastercardMerchantCategoryCode":5814}`)
+	})
+	mux.HandleFunc("/api/v2/merchants/e5f4a3b2-c1d0-4e9f-8a7b-6c5d4e3f2a1b", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	items := []WebHookFeedItem{
+		{
+			FeedItem:              FeedItem{CounterPartyType: "MERCHANT", CounterPartyUID: "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a", CounterPartySubEntityUID: "3c9b8e2f-6d1a-4f7c-8e5b-2a4d6f8b0c1e"},
+			MasterCardFeedDetails: MasterCardFeedItem{MCC: 5812},
+		},
+		{
+			FeedItem:              FeedItem{CounterPartyType: "MERCHANT", CounterPartyUID: "a7e1d4c2-5b3f-4e8a-9c6d-0f1e2d3c4b5a"},
+			MasterCardFeedDetails: MasterCardFeedItem{MCC: 5812},
+		},
+		{
+			FeedItem:              FeedItem{CounterPartyType: "MERCHANT", CounterPartyUID: "e5f4a3b2-c1d0-4e9f-8a7b-6c5d4e3f2a1b"},
+			MasterCardFeedDetails: MasterCardFeedItem{MCC: 5411},
+		},
+		{
+			FeedItem: FeedItem{CounterPartyType: "PAYEE", CounterPartyUID: "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"},
+		},
+	}
+
+	got, err := NewEnricher(client).EnrichWebhook(context.Background(), items)
+	checkNoError(t, err)
+
+	if len(got) != len(items) {
+		t.Fatal("should return an item for each feed item", cross, len(got))
+	}
+	if got[0].MCC == nil || got[0].MCC.Code != 5814 {
+		t.Error("should prefer the MCC of the outlet", cross, got[0].MCC)
+	}
+	if got[1].Outlet != nil || got[1].MCC == nil || got[1].MCC.Code != 5812 {
+		t.Error("should use the card MCC when there is no outlet", cross, got[1].MCC)
+	}
+	if got[2].Merchant != nil || got[2].MCC == nil || got[2].MCC.Code != 5411 {
+		t.Error("should use the card MCC when the merchant is unknown", cross, got[2].MCC)
+	}
+	if got[3].MCC != nil {
+		t.Error("should not decorate items without an MCC", cross, got[3].MCC)
+	}
+}
@@ -38,11 +38,6 @@ type Photo struct {
 	Base64EncodedPhoto string `json:"base64EncodedPhoto"` // A text (base 64) encoded picture to associate with the savings goal
 }
 
-// SpendingCategory is the category associated with a transaction
-type SpendingCategory struct {
-	SpendingCategory string `json:"spendingCategory"`
-}
-
 // DateRange holds two dates that represent a range. It is typically
 // used when providing a range when querying the API.
 type DateRange struct {
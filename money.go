@@ -0,0 +1,344 @@
+package starling
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Errors returned when working with Money. They can be matched using errors.Is.
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrMoneyOverflow    = errors.New("amount out of range")
+	ErrInexactAmount    = errors.New("amount is more precise than the currency allows")
+)
+
+// Money is an exact amount of a currency held in minor units. It has the same
+// shape as Amount, so the two convert freely, and adds arithmetic that refuses
+// to mix currencies along with parsing and formatting that respects the number
+// of minor units of each currency.
+type Money struct {
+	Currency   string `json:"currency"`   // ISO-4217 3 character currency code
+	MinorUnits int64  `json:"minorUnits"` // Amount in the minor units of the given currency
+}
+
+// exponents holds the number of minor-unit digits of ISO-4217 currencies that
+// do not use two.
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// symbols holds the symbols used when formatting and parsing currencies.
+// Symbols shared by several currencies, such as kr, are left out so that a
+// parsed amount is never ambiguous.
+var symbols = map[string]string{
+	"GBP": "£",
+	"EUR": "€",
+	"USD": "$",
+	"JPY": "¥",
+}
+
+// CurrencyExponent returns the number of minor-unit digits of an ISO-4217
+// currency; 2 for GBP, 0 for JPY and 3 for BHD. Unknown currencies are assumed
+// to use two.
+func CurrencyExponent(currency string) int {
+	if e, ok := exponents[strings.ToUpper(currency)]; ok {
+		return e
+	}
+	return 2
+}
+
+// pow10 returns 10 to the power of e.
+func pow10(e int) int64 {
+	p := int64(1)
+	for i := 0; i < e; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// Money returns the amount as Money.
+func (a Amount) Money() Money { return Money(a) }
+
+// Amount returns the money as an Amount.
+func (m Money) Amount() Amount { return Amount(m) }
+
+// MoneyFromMajor converts an amount in major units, such as the float64
+// amounts used by PaymentAmount and PaymentOrder, to Money. It returns
+// ErrInexactAmount if the amount has more decimal places than the currency.
+func MoneyFromMajor(currency string, major float64) (Money, error) {
+	scaled := major * float64(pow10(CurrencyExponent(currency)))
+	minor := math.Round(scaled)
+	if math.IsNaN(scaled) || math.Abs(minor) >= 1<<53 {
+		return Money{}, errors.Wrapf(ErrMoneyOverflow, "%v %s", major, currency)
+	}
+	if math.Abs(scaled-minor) > 1e-6 {
+		return Money{}, errors.Wrapf(ErrInexactAmount, "%v %s", major, currency)
+	}
+	return Money{Currency: currency, MinorUnits: int64(minor)}, nil
+}
+
+// Major returns the amount in major units. Amounts beyond 2^53 minor units
+// lose precision.
+func (m Money) Major() float64 {
+	return float64(m.MinorUnits) / float64(pow10(CurrencyExponent(m.Currency)))
+}
+
+// Money returns the payment amount as Money.
+func (p PaymentAmount) Money() (Money, error) { return MoneyFromMajor(p.Currency, p.Amount) }
+
+// PaymentAmount returns the money as a PaymentAmount.
+func (m Money) PaymentAmount() PaymentAmount {
+	return PaymentAmount{Currency: m.Currency, Amount: m.Major()}
+}
+
+// Money returns the amount of the payment order as Money.
+func (p PaymentOrder) Money() (Money, error) { return MoneyFromMajor(p.Currency, p.Amount) }
+
+// same returns ErrCurrencyMismatch unless m and o are in the same currency.
+func (m Money) same(o Money) error {
+	if !strings.EqualFold(m.Currency, o.Currency) {
+		return errors.Wrapf(ErrCurrencyMismatch, "%s and %s", m.Currency, o.Currency)
+	}
+	return nil
+}
+
+// Add returns m + o. It returns ErrCurrencyMismatch if the currencies differ
+// and ErrMoneyOverflow if the result does not fit in an int64.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.same(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.MinorUnits + o.MinorUnits
+	if (sum > m.MinorUnits) != (o.MinorUnits > 0) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Currency: m.Currency, MinorUnits: sum}, nil
+}
+
+// Sub returns m - o. It returns ErrCurrencyMismatch if the currencies differ
+// and ErrMoneyOverflow if the result does not fit in an int64.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.same(o); err != nil {
+		return Money{}, err
+	}
+	diff := m.MinorUnits - o.MinorUnits
+	if (diff < m.MinorUnits) != (o.MinorUnits > 0) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Currency: m.Currency, MinorUnits: diff}, nil
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{Currency: m.Currency, MinorUnits: -m.MinorUnits}
+}
+
+// Cmp compares m and o, returning -1 if m < o, 0 if they are equal and +1 if
+// m > o. It returns ErrCurrencyMismatch if the currencies differ.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.same(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.MinorUnits < o.MinorUnits:
+		return -1, nil
+	case m.MinorUnits > o.MinorUnits:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool { return m.MinorUnits == 0 }
+
+// Decimal formats the amount in major units without a currency or thousands
+// separators, such as -1234.56.
+func (m Money) Decimal() string {
+	return m.format(false)
+}
+
+// String formats the amount for display, such as £1,234.56, -€3.20 or
+// CHF 10.00. Currencies without a known symbol are prefixed with their code.
+func (m Money) String() string {
+	s := m.format(true)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	cur := strings.ToUpper(m.Currency)
+	if sym, ok := symbols[cur]; ok {
+		s = sym + s
+	} else if cur != "" {
+		s = cur + " " + s
+	}
+
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// format returns the amount in major units, optionally grouping thousands.
+func (m Money) format(group bool) string {
+	// Work with the magnitude as a uint64 so that math.MinInt64 can be negated.
+	minor := uint64(m.MinorUnits)
+	neg := m.MinorUnits < 0
+	if neg {
+		minor = -minor
+	}
+
+	digits := strconv.FormatUint(minor, 10)
+	e := CurrencyExponent(m.Currency)
+	if len(digits) <= e {
+		digits = strings.Repeat("0", e-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-e], digits[len(digits)-e:]
+
+	if group {
+		var b strings.Builder
+		for i, r := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteByte(',')
+			}
+			b.WriteRune(r)
+		}
+		whole = b.String()
+	}
+
+	s := whole
+	if e > 0 {
+		s += "." + frac
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// ParseMoney parses an amount such as "£1,234.56", "-€3.20", "GBP 10",
+// "10.00 GBP" or "12.5". The currency is taken from the symbol or code in s if
+// there is one, and from currency otherwise; an error is returned if neither
+// names a currency or if they disagree. Amounts with more decimal places than
+// the currency allows are rejected with ErrInexactAmount rather than rounded.
+func ParseMoney(s, currency string) (Money, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+
+	neg := false
+	if strings.HasPrefix(s, "-") {
+		neg, s = true, strings.TrimSpace(s[1:])
+	}
+
+	found := ""
+	for cur, sym := range symbols {
+		if strings.HasPrefix(s, sym) {
+			found, s = cur, strings.TrimSpace(s[len(sym):])
+			break
+		}
+	}
+	if found == "" {
+		if code, rest, ok := cutCode(s, true); ok {
+			found, s = code, rest
+		} else if code, rest, ok := cutCode(s, false); ok {
+			found, s = code, rest
+		}
+	}
+
+	if strings.HasPrefix(s, "-") {
+		if neg {
+			return Money{}, errors.Errorf("invalid amount %q", orig)
+		}
+		neg, s = true, strings.TrimSpace(s[1:])
+	}
+
+	switch {
+	case found == "" && currency == "":
+		return Money{}, errors.Errorf("no currency in amount %q", orig)
+	case found == "":
+		found = strings.ToUpper(currency)
+	case currency != "" && !strings.EqualFold(found, currency):
+		return Money{}, errors.Wrapf(ErrCurrencyMismatch, "%q is not %s", orig, currency)
+	}
+
+	minor, err := parseMinor(s, CurrencyExponent(found))
+	if err != nil {
+		return Money{}, errors.Wrapf(err, "invalid amount %q", orig)
+	}
+	if neg {
+		minor = -minor
+	}
+	return Money{Currency: found, MinorUnits: minor}, nil
+}
+
+// cutCode removes a three letter currency code from the start or end of s.
+func cutCode(s string, prefix bool) (code, rest string, ok bool) {
+	if len(s) < 3 {
+		return "", s, false
+	}
+	if prefix {
+		code, rest = s[:3], s[3:]
+	} else {
+		code, rest = s[len(s)-3:], s[:len(s)-3]
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return "", s, false
+		}
+	}
+	return strings.ToUpper(code), strings.TrimSpace(rest), true
+}
+
+// parseMinor parses a non-negative decimal number with optional thousands
+// separators into minor units of a currency with exponent e.
+func parseMinor(s string, e int) (int64, error) {
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+
+	if strings.Contains(whole, ",") {
+		groups := strings.Split(whole, ",")
+		for i, g := range groups {
+			if (i == 0 && (len(g) == 0 || len(g) > 3)) || (i > 0 && len(g) != 3) {
+				return 0, errors.New("misplaced thousands separator")
+			}
+		}
+		whole = strings.Join(groups, "")
+	}
+
+	if whole == "" && frac == "" {
+		return 0, errors.New("no digits")
+	}
+	for _, part := range []string{whole, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return 0, errors.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+
+	if trimmed := strings.TrimRight(frac, "0"); len(trimmed) > e {
+		return 0, ErrInexactAmount
+	}
+	if len(frac) > e {
+		frac = frac[:e]
+	}
+	frac += strings.Repeat("0", e-len(frac))
+
+	if whole+frac == "" {
+		return 0, nil
+	}
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+			return 0, ErrMoneyOverflow
+		}
+		return 0, err
+	}
+	return minor, nil
+}
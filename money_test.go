@@ -0,0 +1,168 @@
+package starling
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCurrencyExponent(t *testing.T) {
+	cases := map[string]int{"GBP": 2, "EUR": 2, "JPY": 0, "jpy": 0, "BHD": 3, "XXX": 2}
+	for cur, want := range cases {
+		if got := CurrencyExponent(cur); got != want {
+			t.Errorf("should use %d minor-unit digits for %s %s %d", want, cur, cross, got)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	cases := []struct {
+		m    Money
+		want string
+	}{
+		{Money{"GBP", 123456}, "£1,234.56"},
+		{Money{"EUR", -320}, "-€3.20"},
+		{Money{"GBP", 5}, "£0.05"},
+		{Money{"JPY", 1234567}, "¥1,234,567"},
+		{Money{"BHD", 1500}, "BHD 1.500"},
+		{Money{"CHF", -100000000}, "-CHF 1,000,000.00"},
+		{Money{"USD", math.MinInt64}, "-$92,233,720,368,547,758.08"},
+	}
+	for _, c := range cases {
+		if got := c.m.String(); got != c.want {
+			t.Errorf("should format %v as %s %s %s", c.m.MinorUnits, c.want, cross, got)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		s, currency string
+		want        Money
+	}{
+		{"£1,234.56", "", Money{"GBP", 123456}},
+		{"-€3.20", "", Money{"EUR", -320}},
+		{"€-3.2", "", Money{"EUR", -320}},
+		{"GBP 10", "", Money{"GBP", 1000}},
+		{"10.00 gbp", "", Money{"GBP", 1000}},
+		{"12.5", "GBP", Money{"GBP", 1250}},
+		{".5", "GBP", Money{"GBP", 50}},
+		{"¥1,000", "JPY", Money{"JPY", 1000}},
+		{"1.234", "BHD", Money{"BHD", 1234}},
+		{"3.100", "GBP", Money{"GBP", 310}},
+	}
+	for _, c := range cases {
+		got, err := ParseMoney(c.s, c.currency)
+		if err != nil {
+			t.Errorf("should parse %q %s %v", c.s, cross, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("should parse %q as %v %s %v", c.s, c.want, cross, got)
+		}
+	}
+}
+
+func TestParseMoneyErrors(t *testing.T) {
+	cases := []struct {
+		s, currency string
+		is          error
+	}{
+		{"12.50", "", nil},
+		{"£12.345", "", ErrInexactAmount},
+		{"1.5", "JPY", ErrInexactAmount},
+		{"£12", "EUR", ErrCurrencyMismatch},
+		{"1,23.00", "GBP", nil},
+		{"--1", "GBP", nil},
+		{"£", "", nil},
+		{"12a", "GBP", nil},
+		{"99999999999999999999", "GBP", ErrMoneyOverflow},
+	}
+	for _, c := range cases {
+		_, err := ParseMoney(c.s, c.currency)
+		if err == nil {
+			t.Errorf("should not parse %q %s", c.s, cross)
+			continue
+		}
+		if c.is != nil && !errors.Is(err, c.is) {
+			t.Errorf("should return %v for %q %s %v", c.is, c.s, cross, err)
+		}
+	}
+}
+
+func TestMoneyRoundTrip(t *testing.T) {
+	for _, m := range []Money{{"GBP", 123456}, {"EUR", -320}, {"JPY", 42}, {"KWD", -7}} {
+		got, err := ParseMoney(m.String(), "")
+		if err != nil || got != m {
+			t.Errorf("should parse %s back to %v %s %v %v", m, m, cross, got, err)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := Money{"GBP", 1050}
+	b := Money{"GBP", 250}
+
+	if got, err := a.Add(b); err != nil || got != (Money{"GBP", 1300}) {
+		t.Error("should add amounts", cross, got, err)
+	}
+	if got, err := b.Sub(a); err != nil || got != (Money{"GBP", -800}) {
+		t.Error("should subtract amounts", cross, got, err)
+	}
+	if got := a.Neg(); got != (Money{"GBP", -1050}) {
+		t.Error("should negate amounts", cross, got)
+	}
+	if got, err := a.Cmp(b); err != nil || got != 1 {
+		t.Error("should compare amounts", cross, got, err)
+	}
+	if got, err := b.Cmp(b); err != nil || got != 0 {
+		t.Error("should compare equal amounts", cross, got, err)
+	}
+
+	eur := Money{"EUR", 100}
+	if _, err := a.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Error("should refuse to add different currencies", cross, err)
+	}
+	if _, err := a.Sub(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Error("should refuse to subtract different currencies", cross, err)
+	}
+	if _, err := a.Cmp(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Error("should refuse to compare different currencies", cross, err)
+	}
+
+	max := Money{"GBP", math.MaxInt64}
+	if _, err := max.Add(Money{"GBP", 1}); !errors.Is(err, ErrMoneyOverflow) {
+		t.Error("should detect overflow when adding", cross, err)
+	}
+	if _, err := max.Neg().Sub(Money{"GBP", 2}); !errors.Is(err, ErrMoneyOverflow) {
+		t.Error("should detect overflow when subtracting", cross, err)
+	}
+}
+
+func TestMoneyConversions(t *testing.T) {
+	a := Amount{Currency: "GBP", MinorUnits: 1024}
+	if got := a.Money().Amount(); got != a {
+		t.Error("should convert to and from Amount", cross, got)
+	}
+
+	m, err := PaymentAmount{Currency: "GBP", Amount: 10.24}.Money()
+	if err != nil || m != a.Money() {
+		t.Error("should convert a PaymentAmount exactly", cross, m, err)
+	}
+	if got := m.PaymentAmount(); got != (PaymentAmount{Currency: "GBP", Amount: 10.24}) {
+		t.Error("should convert to a PaymentAmount", cross, got)
+	}
+
+	m, err = PaymentOrder{Currency: "JPY", Amount: 1500}.Money()
+	if err != nil || m != (Money{"JPY", 1500}) {
+		t.Error("should convert a PaymentOrder amount", cross, m, err)
+	}
+
+	if _, err := MoneyFromMajor("GBP", 0.125); !errors.Is(err, ErrInexactAmount) {
+		t.Error("should refuse amounts with too many decimal places", cross, err)
+	}
+	if _, err := MoneyFromMajor("GBP", math.Inf(1)); !errors.Is(err, ErrMoneyOverflow) {
+		t.Error("should refuse amounts out of range", cross, err)
+	}
+}
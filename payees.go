@@ -0,0 +1,209 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+)
+
+// Payee types
+const (
+	PayeeTypeIndividual = "INDIVIDUAL"
+	PayeeTypeBusiness   = "BUSINESS"
+)
+
+// Bank identifier types used by payee accounts
+const (
+	BankIdentifierSortCode = "SORT_CODE"
+	BankIdentifierSWIFT    = "SWIFT"
+	BankIdentifierIBAN     = "IBAN"
+	BankIdentifierABA      = "ABA"
+	BankIdentifierABAWire  = "ABA_WIRE"
+	BankIdentifierABAACH   = "ABA_ACH"
+)
+
+// Payee is someone the customer can pay
+type Payee struct {
+	UID          string         `json:"payeeUid"`
+	Name         string         `json:"payeeName"`
+	PhoneNumber  string         `json:"phoneNumber"`
+	Type         string         `json:"payeeType"` // INDIVIDUAL or BUSINESS
+	FirstName    string         `json:"firstName"`
+	MiddleName   string         `json:"middleName"`
+	LastName     string         `json:"lastName"`
+	BusinessName string         `json:"businessName"`
+	DateOfBirth  string         `json:"dateOfBirth"` // Date of birth of an individual, formatted as yyyy-mm-dd
+	Accounts     []PayeeAccount `json:"accounts"`
+}
+
+// PayeeAccount is an account belonging to a payee
+type PayeeAccount struct {
+	UID                string   `json:"payeeAccountUid"`
+	ChannelType        string   `json:"payeeChannelType"`
+	Description        string   `json:"description"`
+	DefaultAccount     bool     `json:"defaultAccount"`
+	CountryCode        string   `json:"countryCode"`        // ISO-3166 2 character country code
+	AccountIdentifier  string   `json:"accountIdentifier"`  // Account number or IBAN
+	BankIdentifier     string   `json:"bankIdentifier"`     // Sort code or BIC
+	BankIdentifierType string   `json:"bankIdentifierType"` // SORT_CODE, SWIFT, IBAN, ABA, ABA_WIRE or ABA_ACH
+	LastReferences     []string `json:"lastReferences"`
+}
+
+// payees is a list containing all payees for a customer
+type payees struct {
+	Payees []Payee `json:"payees"`
+}
+
+// PayeeRequest is a request to create a new payee
+type PayeeRequest struct {
+	Name         string                `json:"payeeName"`
+	PhoneNumber  string                `json:"phoneNumber,omitempty"`
+	Type         string                `json:"payeeType"` // INDIVIDUAL or BUSINESS
+	FirstName    string                `json:"firstName,omitempty"`
+	MiddleName   string                `json:"middleName,omitempty"`
+	LastName     string                `json:"lastName,omitempty"`
+	BusinessName string                `json:"businessName,omitempty"`
+	DateOfBirth  string                `json:"dateOfBirth,omitempty"`
+	Accounts     []PayeeAccountRequest `json:"accounts"`
+}
+
+// PayeeAccountRequest is a request to add an account to a payee
+type PayeeAccountRequest struct {
+	Description        string `json:"description"`
+	DefaultAccount     bool   `json:"defaultAccount"`
+	CountryCode        string `json:"countryCode"`
+	AccountIdentifier  string `json:"accountIdentifier"`
+	BankIdentifier     string `json:"bankIdentifier"`
+	BankIdentifierType string `json:"bankIdentifierType"`
+}
+
+// payeeCreationResponse represents the response after attempting to create a payee
+type payeeCreationResponse struct {
+	UID     string        `json:"payeeUid"`
+	Success bool          `json:"success"`
+	Errors  []ErrorDetail `json:"errors"`
+}
+
+// payeeAccountCreationResponse represents the response after attempting to add an account to a payee
+type payeeAccountCreationResponse struct {
+	UID     string        `json:"payeeAccountUid"`
+	Success bool          `json:"success"`
+	Errors  []ErrorDetail `json:"errors"`
+}
+
+// detailErrors returns the errors in an unsuccessful response.
+func detailErrors(success bool, details []ErrorDetail) error {
+	if success || len(details) == 0 {
+		return nil
+	}
+	ers := make(Errors, len(details))
+	for i, v := range details {
+		ers[i] = v.Message
+	}
+	return ers
+}
+
+// Payees returns the payees for the current customer. It also returns the http response in case
+// this is required for further processing. An error will be returned if unable to retrieve the
+// payees from the API.
+func (c *Client) Payees(ctx context.Context) ([]Payee, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/payees", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var p payees
+	resp, err := c.Do(ctx, req, &p)
+	if err != nil {
+		return p.Payees, resp, err
+	}
+
+	return p.Payees, resp, nil
+}
+
+// CreatePayee creates a payee along with any accounts included in the request. It returns the
+// UID of the new payee and the http response in case this is required for further processing.
+// An error will be returned if the API is unable to create the payee.
+func (c *Client) CreatePayee(ctx context.Context, p PayeeRequest) (string, *http.Response, error) {
+	req, err := c.NewRequest("PUT", "/api/v2/payees", p)
+	if err != nil {
+		return "", nil, err
+	}
+	// Each PUT creates another payee, so a retried request could create a duplicate.
+	req = markNotRetryable(req)
+
+	var pResp payeeCreationResponse
+	resp, err := c.Do(ctx, req, &pResp)
+	if err != nil {
+		return "", resp, err
+	}
+
+	if err := detailErrors(pResp.Success, pResp.Errors); err != nil {
+		return "", resp, err
+	}
+	return pResp.UID, resp, nil
+}
+
+// DeletePayee deletes a payee and all of their accounts. It returns the http response in case
+// this is required for further processing. An error is returned on failure.
+func (c *Client) DeletePayee(ctx context.Context, payeeUID string) (*http.Response, error) {
+	req, err := c.NewRequest("DELETE", "/api/v2/payees/"+payeeUID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(ctx, req, nil)
+	return resp, err
+}
+
+// CreatePayeeAccount adds an account to an existing payee. It returns the UID of the new payee
+// account and the http response in case this is required for further processing. An error will
+// be returned if the API is unable to create the account.
+func (c *Client) CreatePayeeAccount(ctx context.Context, payeeUID string, a PayeeAccountRequest) (string, *http.Response, error) {
+	req, err := c.NewRequest("PUT", "/api/v2/payees/"+payeeUID+"/account", a)
+	if err != nil {
+		return "", nil, err
+	}
+	// Each PUT adds another account, so a retried request could add a duplicate.
+	req = markNotRetryable(req)
+
+	var aResp payeeAccountCreationResponse
+	resp, err := c.Do(ctx, req, &aResp)
+	if err != nil {
+		return "", resp, err
+	}
+
+	if err := detailErrors(aResp.Success, aResp.Errors); err != nil {
+		return "", resp, err
+	}
+	return aResp.UID, resp, nil
+}
+
+// DeletePayeeAccount deletes an account belonging to a payee. It returns the http response in
+// case this is required for further processing. An error is returned on failure.
+func (c *Client) DeletePayeeAccount(ctx context.Context, payeeUID, accountUID string) (*http.Response, error) {
+	req, err := c.NewRequest("DELETE", "/api/v2/payees/"+payeeUID+"/account/"+accountUID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(ctx, req, nil)
+	return resp, err
+}
+
+// PayeeImage returns the image associated with a payee. It also returns the http response in
+// case this is required for further processing. An error will be returned if unable to retrieve
+// the image from the API.
+func (c *Client) PayeeImage(ctx context.Context, payeeUID string) (*Photo, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/payees/"+payeeUID+"/image", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var photo *Photo
+	resp, err := c.Do(ctx, req, &photo)
+	if err != nil {
+		return photo, resp, err
+	}
+
+	return photo, resp, nil
+}
@@ -0,0 +1,295 @@
+package starling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var payeesTestCases = []struct {
+	name string
+	mock string
+}{
+	{
+		name: "no payees",
+		mock: `{"payees": []}`,
+	},
+	{
+		name: "individual and business payees",
+		mock: `{
+			"payees": [
+				{
+					"payeeUid": "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e",
+					"payeeName": "Jane Smith",
+					"phoneNumber": "+447700900123",
+					"payeeType": "INDIVIDUAL",
+					"firstName": "Jane",
+					"lastName": "Smith",
+					"dateOfBirth": "1985-03-14",
+					"accounts": [
+						{
+							"payeeAccountUid": "9c4d2e1f-6a7b-4c8d-9e0f-1a2b3c4d5e6f",
+							"payeeChannelType": "BANK_ACCOUNT",
+							"description": "Current account",
+							"defaultAccount": true,
+							"countryCode": "GB",
+							"accountIdentifier": "12345678",
+							"bankIdentifier": "608371",
+							"bankIdentifierType": "SORT_CODE",
+							"lastReferences": ["RENT JUNE", "RENT MAY"]
+						}
+					]
+				},
+				{
+					"payeeUid": "7f3e2d1c-0b9a-4876-a5b4-c3d2e1f0a9b8",
+					"payeeName": "Widgets GmbH",
+					"payeeType": "BUSINESS",
+					"businessName": "Widgets GmbH",
+					"accounts": [
+						{
+							"payeeAccountUid": "1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e",
+							"payeeChannelType": "BANK_ACCOUNT",
+							"description": "EUR account",
+							"defaultAccount": true,
+							"countryCode": "DE",
+							"accountIdentifier": "DE89370400440532013000",
+							"bankIdentifier": "COBADEFFXXX",
+							"bankIdentifierType": "SWIFT"
+						}
+					]
+				}
+			]
+		}`,
+	},
+}
+
+func TestPayees(t *testing.T) {
+	for _, tc := range payeesTestCases {
+		t.Run(tc.name, func(st *testing.T) {
+			testPayees(st, tc.name, tc.mock)
+		})
+	}
+}
+
+func testPayees(t *testing.T, name, mock string) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/payees", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, mock)
+	})
+
+	got, resp, err := client.Payees(context.Background())
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusOK)
+
+	want := &payees{}
+	json.Unmarshal([]byte(mock), want)
+
+	if !reflect.DeepEqual(got, want.Payees) {
+		t.Error("should return a list of payees matching the mock response", cross)
+	}
+}
+
+func TestPayeesForbidden(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/payees", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	got, resp, err := client.Payees(context.Background())
+	checkHasError(t, err)
+	checkStatus(t, resp, http.StatusForbidden)
+
+	if got != nil {
+		t.Error("should not return any payees", cross)
+	}
+}
+
+func TestCreatePayee(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	payee := PayeeRequest{
+		Name:      "Jane Smith",
+		Type:      PayeeTypeIndividual,
+		FirstName: "Jane",
+		LastName:  "Smith",
+		Accounts: []PayeeAccountRequest{
+			{
+				Description:        "Current account",
+				DefaultAccount:     true,
+				CountryCode:        "GB",
+				AccountIdentifier:  "12345678",
+				BankIdentifier:     "608371",
+				BankIdentifierType: BankIdentifierSortCode,
+			},
+		},
+	}
+
+	mux.HandleFunc("/api/v2/payees", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+
+		var got PayeeRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal("should send a request that the API can parse", cross, err)
+		}
+		if !reflect.DeepEqual(got, payee) {
+			t.Error("should send a payee that matches the request", cross, got)
+		}
+
+		fmt.Fprint(w, `{"payeeUid":"2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e","success":true,"errors":[]}`)
+	})
+
+	uid, _, err := client.CreatePayee(context.Background(), payee)
+	checkNoError(t, err)
+
+	if uid != "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e" {
+		t.Error("should return the UID of the new payee", cross, uid)
+	}
+}
+
+func TestCreatePayeeUnsuccessful(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/payees", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"success":false,"errors":[{"message":"INVALID_ACCOUNT_IDENTIFIER"}]}`)
+	})
+
+	_, _, err := client.CreatePayee(context.Background(), PayeeRequest{Name: "Jane Smith"})
+	checkHasError(t, err)
+
+	if err != nil && err.Error() != "INVALID_ACCOUNT_IDENTIFIER" {
+		t.Error("should return the errors in the response", cross, err)
+	}
+}
+
+func TestCreatePayeeNotRetried(t *testing.T) {
+	cases := []struct {
+		name   string
+		path   string
+		create func(*Client) error
+	}{
+		{
+			name: "payee",
+			path: "/api/v2/payees",
+			create: func(c *Client) error {
+				_, _, err := c.CreatePayee(context.Background(), PayeeRequest{Name: "Jane Smith"})
+				return err
+			},
+		},
+		{
+			name: "payee account",
+			path: "/api/v2/payees/2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e/account",
+			create: func(c *Client) error {
+				_, _, err := c.CreatePayeeAccount(context.Background(), "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e", PayeeAccountRequest{})
+				return err
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tc *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+			client.retry = testRetryPolicy()
+
+			calls := 0
+			mux.HandleFunc(c.path, func(w http.ResponseWriter, r *http.Request) {
+				checkMethod(tc, r, http.MethodPut)
+				calls++
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			checkHasError(tc, c.create(client))
+			if calls != 1 {
+				tc.Error("should not retry a request that creates a payee", cross, calls)
+			}
+		})
+	}
+}
+
+func TestDeletePayee(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/payees/2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodDelete)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := client.DeletePayee(context.Background(), "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e")
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusNoContent)
+}
+
+func TestCreatePayeeAccount(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	account := PayeeAccountRequest{
+		Description:        "Savings",
+		CountryCode:        "GB",
+		AccountIdentifier:  "GB33BUKB20201555555555",
+		BankIdentifier:     "BUKBGB22",
+		BankIdentifierType: BankIdentifierIBAN,
+	}
+
+	mux.HandleFunc("/api/v2/payees/2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e/account", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+
+		var got PayeeAccountRequest
+		json.NewDecoder(r.Body).Decode(&got)
+		if !reflect.DeepEqual(got, account) {
+			t.Error("should send an account that matches the request", cross, got)
+		}
+
+		fmt.Fprint(w, `{"payeeAccountUid":"5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a","success":true,"errors":[]}`)
+	})
+
+	uid, _, err := client.CreatePayeeAccount(context.Background(), "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e", account)
+	checkNoError(t, err)
+
+	if uid != "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a" {
+		t.Error("should return the UID of the new payee account", cross, uid)
+	}
+}
+
+func TestDeletePayeeAccount(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/payees/2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e/account/5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodDelete)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := client.DeletePayeeAccount(context.Background(), "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e", "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a")
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusNoContent)
+}
+
+func TestPayeeImage(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/payees/2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e/image", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"base64EncodedPhoto":"aW1hZ2U="}`)
+	})
+
+	got, _, err := client.PayeeImage(context.Background(), "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e")
+	checkNoError(t, err)
+
+	if got == nil || got.Base64EncodedPhoto != "aW1hZ2U=" {
+		t.Error("should return the payee image", cross, got)
+	}
+}
@@ -46,13 +46,16 @@ type halPaymentOrders struct {
 	Embedded *paymentOrders `json:"_embedded"`
 }
 
-// PaymentAmount represents the currency and amount of a payment
+// PaymentAmount represents the currency and amount of a payment. Amount is in major units; use
+// Money to convert it to exact minor units.
 type PaymentAmount struct {
 	Currency string  `json:"currency"`
 	Amount   float64 `json:"amount"`
 }
 
 // MakeLocalPayment creates a local payment.
+//
+// Deprecated: the v1 payments API is deprecated. Use InstructLocalPayment to pay a payee account.
 func (c *Client) MakeLocalPayment(ctx context.Context, p LocalPayment) (*http.Response, error) {
 	req, err := c.NewRequest("POST", "/api/v1/payments/local", p)
 	if err != nil {
@@ -64,6 +67,8 @@ func (c *Client) MakeLocalPayment(ctx context.Context, p LocalPayment) (*http.Re
 }
 
 // CreateScheduledPayment creates a scheduled payment. It returns the UID for the scheduled payment.
+//
+// Deprecated: the v1 payments API is deprecated. Use CreateStandingOrder instead.
 func (c *Client) CreateScheduledPayment(ctx context.Context, p ScheduledPayment) (string, *http.Response, error) {
 	req, err := c.NewRequest("POST", "/api/v1/payments/scheduled", p)
 	if err != nil {
@@ -82,6 +87,8 @@ func (c *Client) CreateScheduledPayment(ctx context.Context, p ScheduledPayment)
 
 // ScheduledPayments retrieves a list of all the payment orders on the customer account. These may be
 // orders for previous immediate payments or scheduled payment orders for future or on-going payments.
+//
+// Deprecated: the v1 payments API is deprecated. Use StandingOrders instead.
 func (c *Client) ScheduledPayments(ctx context.Context) ([]PaymentOrder, *http.Response, error) {
 	req, err := c.NewRequest("GET", "/api/v1/payments/scheduled", nil)
 
@@ -102,3 +109,96 @@ func (c *Client) ScheduledPayments(ctx context.Context) ([]PaymentOrder, *http.R
 
 	return hPO.Embedded.PaymentOrders, resp, err
 }
+
+// Payment statuses reported by PaymentOrderStatus
+const (
+	PaymentStatusPending  = "PENDING"
+	PaymentStatusAccepted = "ACCEPTED"
+	PaymentStatusRejected = "REJECTED"
+)
+
+// LocalPaymentInstruction is a request to pay an account belonging to a payee
+type LocalPaymentInstruction struct {
+	ExternalIdentifier         string           `json:"externalIdentifier"` // Caller supplied identifier used to detect duplicate requests
+	DestinationPayeeAccountUID string           `json:"destinationPayeeAccountUid"`
+	Reference                  string           `json:"reference"`
+	Amount                     Amount           `json:"amount"`
+	SpendingCategory           SpendingCategory `json:"spendingCategory,omitempty"`
+}
+
+// ConsentInformation describes whether the customer must approve a payment before it is sent
+type ConsentInformation struct {
+	ApprovalType string `json:"approvalType"`
+}
+
+// PaymentOrderConfirmation is returned when a payment has been instructed
+type PaymentOrderConfirmation struct {
+	PaymentOrderUID    string             `json:"paymentOrderUid"`
+	ConsentInformation ConsentInformation `json:"consentInformation"`
+}
+
+// PaymentStatusDetails is the status of a payment and the reason for it
+type PaymentStatusDetails struct {
+	Status      string `json:"paymentStatus"` // PENDING, ACCEPTED or REJECTED
+	Description string `json:"description"`
+}
+
+// PaymentDetail is a payment made as part of a payment order
+type PaymentDetail struct {
+	PaymentUID      string               `json:"paymentUid"`
+	Amount          Amount               `json:"amount"`
+	Reference       string               `json:"reference"`
+	PayeeUID        string               `json:"payeeUid"`
+	PayeeAccountUID string               `json:"payeeAccountUid"`
+	CreatedAt       string               `json:"createdAt"`
+	CompletedAt     string               `json:"completedAt"`
+	RejectedAt      string               `json:"rejectedAt"`
+	StatusDetails   PaymentStatusDetails `json:"paymentStatusDetails"`
+}
+
+// Done reports whether the payment has reached a final status.
+func (p PaymentDetail) Done() bool {
+	return p.StatusDetails.Status == PaymentStatusAccepted || p.StatusDetails.Status == PaymentStatusRejected
+}
+
+// paymentDetails is a list of the payments made for a payment order
+type paymentDetails struct {
+	Payments []PaymentDetail `json:"payments"`
+}
+
+// InstructLocalPayment pays an account belonging to a payee from the given account and category.
+// The ExternalIdentifier should be unique to the payment so that a request retried after a
+// network failure is not paid twice. It returns the payment order UID, which can be passed to
+// PaymentOrderStatus, and the http response in case this is required for further processing.
+func (c *Client) InstructLocalPayment(ctx context.Context, accountUID, categoryUID string, p LocalPaymentInstruction) (*PaymentOrderConfirmation, *http.Response, error) {
+	req, err := c.NewRequest("PUT", "/api/v2/payments/local/account/"+accountUID+"/category/"+categoryUID, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var poc *PaymentOrderConfirmation
+	resp, err := c.Do(ctx, req, &poc)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return poc, resp, nil
+}
+
+// PaymentOrderStatus returns the payments made for a payment order along with their status. It
+// also returns the http response in case this is required for further processing. An error will
+// be returned if unable to retrieve the payments from the API.
+func (c *Client) PaymentOrderStatus(ctx context.Context, paymentOrderUID string) ([]PaymentDetail, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/payments/local/payment-order/"+paymentOrderUID+"/payments", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pd paymentDetails
+	resp, err := c.Do(ctx, req, &pd)
+	if err != nil {
+		return pd.Payments, resp, err
+	}
+
+	return pd.Payments, resp, nil
+}
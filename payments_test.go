@@ -346,3 +346,102 @@ func TestCreateScheduledPaymentForbidden(t *testing.T) {
 		t.Error("should not return a payment ID")
 	}
 }
+
+func TestInstructLocalPayment(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	payment := LocalPaymentInstruction{
+		ExternalIdentifier:         "rent-2020-06",
+		DestinationPayeeAccountUID: "9c4d2e1f-6a7b-4c8d-9e0f-1a2b3c4d5e6f",
+		Reference:                  "RENT JUNE",
+		Amount:                     Amount{Currency: "GBP", MinorUnits: 85000},
+		SpendingCategory:           "BILLS_AND_SERVICES",
+	}
+
+	mux.HandleFunc("/api/v2/payments/local/account/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/category/cc1e5b58-0d63-4d1e-9f7c-5f7b5b0d7e41", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+
+		var got LocalPaymentInstruction
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal("should send a request that the API can parse", cross, err)
+		}
+		if !reflect.DeepEqual(got, payment) {
+			t.Error("should send a payment that matches the request", cross, got)
+		}
+
+		fmt.Fprint(w, `{
+			"paymentOrderUid": "e4f1a2b3-c4d5-4e6f-8a9b-0c1d2e3f4a5b",
+			"consentInformation": {"approvalType": "NO_APPROVAL_REQUIRED"}
+		}`)
+	})
+
+	got, _, err := client.InstructLocalPayment(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", "cc1e5b58-0d63-4d1e-9f7c-5f7b5b0d7e41", payment)
+	checkNoError(t, err)
+
+	want := &PaymentOrderConfirmation{
+		PaymentOrderUID:    "e4f1a2b3-c4d5-4e6f-8a9b-0c1d2e3f4a5b",
+		ConsentInformation: ConsentInformation{ApprovalType: "NO_APPROVAL_REQUIRED"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("should return the payment order confirmation", cross, got)
+	}
+}
+
+func TestInstructLocalPaymentInsufficientFunds(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/payments/local/account/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/category/cc1e5b58-0d63-4d1e-9f7c-5f7b5b0d7e41", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errors":[{"message":"INSUFFICIENT_FUNDS"}],"success":false}`)
+	})
+
+	got, _, err := client.InstructLocalPayment(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", "cc1e5b58-0d63-4d1e-9f7c-5f7b5b0d7e41", LocalPaymentInstruction{})
+	checkHasError(t, err)
+
+	if !IsInsufficientFunds(err) {
+		t.Error("should report insufficient funds", cross, err)
+	}
+	if got != nil {
+		t.Error("should not return a confirmation", cross)
+	}
+}
+
+func TestPaymentOrderStatus(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mock := `{
+		"payments": [
+			{
+				"paymentUid": "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+				"amount": {"currency": "GBP", "minorUnits": 85000},
+				"reference": "RENT JUNE",
+				"payeeUid": "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e",
+				"payeeAccountUid": "9c4d2e1f-6a7b-4c8d-9e0f-1a2b3c4d5e6f",
+				"createdAt": "2020-06-01T09:00:00.000Z",
+				"completedAt": "2020-06-01T09:00:02.000Z",
+				"paymentStatusDetails": {"paymentStatus": "ACCEPTED", "description": "ACCEPTED"}
+			}
+		]
+	}`
+
+	mux.HandleFunc("/api/v2/payments/local/payment-order/e4f1a2b3-c4d5-4e6f-8a9b-0c1d2e3f4a5b/payments", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, mock)
+	})
+
+	got, _, err := client.PaymentOrderStatus(context.Background(), "e4f1a2b3-c4d5-4e6f-8a9b-0c1d2e3f4a5b")
+	checkNoError(t, err)
+
+	want := &paymentDetails{}
+	json.Unmarshal([]byte(mock), want)
+
+	if !reflect.DeepEqual(got, want.Payments) {
+		t.Error("should return payments matching the mock response", cross)
+	}
+	if len(got) != 1 || !got[0].Done() {
+		t.Error("should report an accepted payment as done", cross)
+	}
+}
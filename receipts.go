@@ -0,0 +1,70 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+)
+
+// Receipt is an itemised receipt for a feed item, typically provided by the merchant or read from
+// a paper receipt
+type Receipt struct {
+	UID                string           `json:"receiptUid,omitempty"`
+	Identifier         string           `json:"receiptIdentifier"` // Identifier of the receipt in the system that created it
+	MerchantIdentifier string           `json:"merchantIdentifier,omitempty"`
+	MerchantAddress    string           `json:"merchantAddress,omitempty"`
+	TotalAmount        Amount           `json:"totalAmount"`
+	TotalTax           Amount           `json:"totalTax"`
+	AuthCode           string           `json:"authCode,omitempty"`  // Card payment authorisation code
+	CardLast4          string           `json:"cardLast4,omitempty"` // Last four digits of the card used to pay
+	ProviderName       string           `json:"providerName,omitempty"`
+	Items              []ReceiptItem    `json:"items"`
+	Notes              []ReceiptNote    `json:"notes,omitempty"`
+	Merchant           *ReceiptMerchant `json:"merchant,omitempty"`
+}
+
+// ReceiptItem is a line on a receipt
+type ReceiptItem struct {
+	UID         string `json:"receiptItemUid,omitempty"`
+	Description string `json:"description"`
+	Quantity    int32  `json:"quantity"`
+	Amount      Amount `json:"amount"` // Total for the line, not the price of each
+	Tax         Amount `json:"tax"`
+	URL         string `json:"url,omitempty"`
+}
+
+// ReceiptNote is a note printed on a receipt, such as a returns policy
+type ReceiptNote struct {
+	UID         string `json:"noteUid,omitempty"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+}
+
+// ReceiptMerchant describes the merchant named on a receipt
+type ReceiptMerchant struct {
+	Name            string `json:"name"`
+	Website         string `json:"website,omitempty"`
+	TwitterUsername string `json:"twitterUsername,omitempty"`
+}
+
+// receiptCreationResponse represents the response after attempting to create a receipt
+type receiptCreationResponse struct {
+	UID string `json:"receiptUid"`
+}
+
+// CreateReceipt adds a receipt to a feed item, replacing any receipt with the same identifier. It
+// returns the UID of the receipt and the http response in case this is required for further
+// processing. An error will be returned if the API is unable to store the receipt.
+func (c *Client) CreateReceipt(ctx context.Context, act, cat, itm string, r Receipt) (string, *http.Response, error) {
+	req, err := c.NewRequest("PUT", "/api/v2/feed/account/"+act+"/category/"+cat+"/"+itm+"/receipt", r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var rResp receiptCreationResponse
+	resp, err := c.Do(ctx, req, &rResp)
+	if err != nil {
+		return "", resp, err
+	}
+
+	return rResp.UID, resp, nil
+}
@@ -0,0 +1,48 @@
+package starling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCreateReceipt(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	receipt := Receipt{
+		Identifier:      "ocr-20200602-0001",
+		MerchantAddress: "1 High Street, London",
+		TotalAmount:     Amount{Currency: "GBP", MinorUnits: 1250},
+		TotalTax:        Amount{Currency: "GBP", MinorUnits: 208},
+		CardLast4:       "1234",
+		Items: []ReceiptItem{
+			{Description: "Sandwich", Quantity: 2, Amount: Amount{Currency: "GBP", MinorUnits: 900}, Tax: Amount{Currency: "GBP", MinorUnits: 150}},
+			{Description: "Coffee", Quantity: 1, Amount: Amount{Currency: "GBP", MinorUnits: 350}, Tax: Amount{Currency: "GBP", MinorUnits: 58}},
+		},
+		Merchant: &ReceiptMerchant{Name: "Corner Cafe"},
+	}
+
+	mux.HandleFunc("/api/v2/feed/account/30aa7ab8-4389-4658-a4f8-0bc6d0015ba0/category/c423ab8d-9a6a-44b2-8db6-ac6000fe58e0/dbb59f1c-39e6-4558-87ba-11c142965393/receipt", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+
+		var got Receipt
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal("should send a request that the API can parse", cross, err)
+		}
+		if !reflect.DeepEqual(got, receipt) {
+			t.Error("should send a receipt that matches the request", cross, got)
+		}
+		fmt.Fprint(w, `{"receiptUid":"c0d1e2f3-a4b5-4c6d-8e7f-9a0b1c2d3e4f"}`)
+	})
+
+	uid, _, err := client.CreateReceipt(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", "dbb59f1c-39e6-4558-87ba-11c142965393", receipt)
+	checkNoError(t, err)
+
+	if uid != "c0d1e2f3-a4b5-4c6d-8e7f-9a0b1c2d3e4f" {
+		t.Error("should return the UID of the receipt", cross, uid)
+	}
+}
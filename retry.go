@@ -0,0 +1,214 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures how the client retries requests that fail with a
+// transient error. Requests are only retried if they use an idempotent method
+// (GET, HEAD, OPTIONS, PUT, DELETE) or have been marked as safe to retry with
+// MarkRetryable. Retries never extend beyond the deadline of the context
+// passed to Do.
+type RetryPolicy struct {
+	MaxAttempts int           // Total number of attempts, including the first; values below 2 disable retries
+	MinBackoff  time.Duration // Delay before the first retry; defaults to 250ms
+	MaxBackoff  time.Duration // Upper bound on the delay between attempts, including any Retry-After value; defaults to 10s
+}
+
+// Backoff used by DefaultRetryPolicy and for RetryPolicy fields that are not set.
+const (
+	defaultMinBackoff = 250 * time.Millisecond
+	defaultMaxBackoff = 10 * time.Second
+)
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for most callers: up to
+// four attempts with delays starting at 250ms and capped at 10s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  defaultMinBackoff,
+		MaxBackoff:  defaultMaxBackoff,
+	}
+}
+
+// RetryError is returned by Do when a request was attempted more than once. It
+// records the number of attempts made and wraps the error from the final attempt.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error from the final attempt.
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// Cause returns the error from the final attempt.
+func (e *RetryError) Cause() error { return e.Err }
+
+// Temporary indicates if the error from the final attempt is temporary.
+func (e *RetryError) Temporary() bool {
+	if t, ok := e.Err.(Error); ok {
+		return t.Temporary()
+	}
+	return false
+}
+
+// Attempts returns the number of attempts recorded on err. Errors that were not
+// retried report a single attempt.
+func Attempts(err error) int {
+	if err == nil {
+		return 0
+	}
+	if e, ok := err.(*RetryError); ok {
+		return e.Attempts
+	}
+	return 1
+}
+
+type retryableKey struct{}
+
+// MarkRetryable returns a copy of req that the client may retry even though
+// its method is not idempotent. Only mark requests where the API guarantees
+// that a repeated request has no additional effect.
+func MarkRetryable(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), retryableKey{}, true))
+}
+
+type noRetryKey struct{}
+
+// markNotRetryable returns a copy of req that the client never retries, for
+// requests whose method is idempotent but whose effect in the API is not.
+func markNotRetryable(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), noRetryKey{}, true))
+}
+
+// canRetry reports whether the request may be sent more than once.
+func canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if marked, _ := req.Context().Value(noRetryKey{}).(bool); marked {
+		return false
+	}
+	if marked, _ := req.Context().Value(retryableKey{}).(bool); marked {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryableStatus reports whether a response status indicates a transient failure.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+var (
+	jitterMu  sync.Mutex
+	jitterRnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// limits returns the policy's backoff bounds, using the defaults for those not set.
+func (p *RetryPolicy) limits() (min, max time.Duration) {
+	min, max = p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+		if min > max {
+			max = min
+		}
+	}
+	return min, max
+}
+
+// backoff returns the delay before the given retry, where retry 1 is the first
+// retry. The delay grows exponentially and is jittered into the upper half of
+// the window so that concurrent clients spread out.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	min, max := p.limits()
+	d := float64(min) * math.Pow(2, float64(retry-1))
+	if d > float64(max) {
+		d = float64(max)
+	}
+
+	jitterMu.Lock()
+	j := jitterRnd.Float64()
+	jitterMu.Unlock()
+
+	return time.Duration(d/2 + j*d/2)
+}
+
+// delay returns the time to wait before the given retry, preferring the
+// Retry-After header of the previous response when one was provided.
+func (p *RetryPolicy) delay(retry int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if _, max := p.limits(); d > max {
+				d = max
+			}
+			return d
+		}
+	}
+	return p.backoff(retry)
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until the context is done. It returns false without
+// waiting if the context deadline would pass before d elapses.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
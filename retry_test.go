@@ -0,0 +1,276 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestDoRetry(t *testing.T) {
+	t.Run("retries a transient failure", func(tc *testing.T) {
+		client, mux, _, teardown := setup()
+		defer teardown()
+		client.retry = testRetryPolicy()
+
+		calls := 0
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			checkMethod(tc, r, http.MethodGet)
+			calls++
+			if calls < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"A":"a"}`)
+		})
+
+		got := new(struct{ A string })
+		req, _ := client.NewRequest("GET", ".", nil)
+		resp, err := client.Do(context.Background(), req, got)
+
+		checkNoError(tc, err)
+		checkStatus(tc, resp, http.StatusOK)
+		if calls != 3 {
+			tc.Error("should retry until the request succeeds", cross, calls)
+		}
+		if got.A != "a" {
+			tc.Error("should decode the final response", cross, got.A)
+		}
+	})
+
+	t.Run("reports attempts when retries are exhausted", func(tc *testing.T) {
+		client, mux, _, teardown := setup()
+		defer teardown()
+		client.retry = testRetryPolicy()
+
+		calls := 0
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		req, _ := client.NewRequest("GET", ".", nil)
+		resp, err := client.Do(context.Background(), req, nil)
+
+		checkHasError(tc, err)
+		checkStatus(tc, resp, http.StatusInternalServerError)
+		if calls != 3 {
+			tc.Error("should make MaxAttempts attempts", cross, calls)
+		}
+		re, ok := err.(*RetryError)
+		if !ok {
+			tc.Fatalf("should return a *RetryError %s %T", cross, err)
+		}
+		if got := Attempts(err); got != 3 {
+			tc.Error("should report the number of attempts", cross, got)
+		}
+
+		last, ok := errors.Cause(err).(*APIError)
+		if !ok || last != re.Err || last.StatusCode != http.StatusInternalServerError {
+			tc.Errorf("should return the error from the final attempt as the cause %s %T", cross, errors.Cause(err))
+		}
+		if !ok || err.Error() != "after 3 attempts: "+last.Error() {
+			tc.Error("should report the attempts and the final error", cross, err.Error())
+		}
+		if !re.Temporary() {
+			tc.Error("should be temporary when the final error is", cross)
+		}
+	})
+
+	t.Run("does not retry a permanent failure", func(tc *testing.T) {
+		client, mux, _, teardown := setup()
+		defer teardown()
+		client.retry = testRetryPolicy()
+
+		calls := 0
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `["bad request"]`)
+		})
+
+		req, _ := client.NewRequest("GET", ".", nil)
+		_, err := client.Do(context.Background(), req, nil)
+
+		checkHasError(tc, err)
+		if calls != 1 {
+			tc.Error("should not retry a client error", cross, calls)
+		}
+		if got := Attempts(err); got != 1 {
+			tc.Error("should report a single attempt", cross, got)
+		}
+	})
+
+	t.Run("does not retry a POST request", func(tc *testing.T) {
+		client, mux, _, teardown := setup()
+		defer teardown()
+		client.retry = testRetryPolicy()
+
+		calls := 0
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		req, _ := client.NewRequest("POST", ".", struct{ A string }{"a"})
+		_, err := client.Do(context.Background(), req, nil)
+
+		checkHasError(tc, err)
+		if calls != 1 {
+			tc.Error("should not retry a non-idempotent request", cross, calls)
+		}
+	})
+
+	t.Run("retries a POST request marked as retryable", func(tc *testing.T) {
+		client, mux, _, teardown := setup()
+		defer teardown()
+		client.retry = testRetryPolicy()
+
+		var bodies []string
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			checkMethod(tc, r, http.MethodPost)
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		req, _ := client.NewRequest("POST", ".", struct{ A string }{"a"})
+		resp, err := client.Do(context.Background(), MarkRetryable(req), nil)
+
+		checkNoError(tc, err)
+		checkStatus(tc, resp, http.StatusAccepted)
+		if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+			tc.Error("should resend the request body on retry", cross, bodies)
+		}
+	})
+
+	t.Run("honours Retry-After", func(tc *testing.T) {
+		client, mux, _, teardown := setup()
+		defer teardown()
+		client.retry = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Minute}
+
+		var first time.Time
+		var gap time.Duration
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if first.IsZero() {
+				first = time.Now()
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			gap = time.Since(first)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req, _ := client.NewRequest("GET", ".", nil)
+		_, err := client.Do(context.Background(), req, nil)
+
+		checkNoError(tc, err)
+		if gap < time.Second {
+			tc.Error("should wait for the Retry-After delay", cross, gap)
+		}
+	})
+
+	t.Run("stops retrying at the context deadline", func(tc *testing.T) {
+		client, mux, _, teardown := setup()
+		defer teardown()
+		client.retry = &RetryPolicy{MaxAttempts: 5, MinBackoff: time.Minute, MaxBackoff: time.Minute}
+
+		calls := 0
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		start := time.Now()
+		req, _ := client.NewRequest("GET", ".", nil)
+		_, err := client.Do(ctx, req, nil)
+
+		checkHasError(tc, err)
+		if calls != 1 {
+			tc.Error("should not retry when the backoff exceeds the deadline", cross, calls)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			tc.Error("should return without waiting for the deadline", cross)
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "3", want: 3 * time.Second, ok: true},
+		{value: "-1", ok: false},
+		{value: now.Add(2 * time.Second).Format(http.TimeFormat), want: 2 * time.Second, ok: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+
+	for _, c := range cases {
+		got, ok := retryAfter(c.value, now)
+		if ok != c.ok || got != c.want {
+			t.Errorf("should parse Retry-After %q %s %v %v", c.value, cross, got, ok)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retry := 1; retry <= 8; retry++ {
+		d := p.backoff(retry)
+		if d > p.MaxBackoff {
+			t.Error("should not exceed MaxBackoff", cross, retry, d)
+		}
+		if retry == 1 && (d < 50*time.Millisecond || d > 100*time.Millisecond) {
+			t.Error("should start from MinBackoff", cross, d)
+		}
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	p := DefaultRetryPolicy()
+	if p.MaxAttempts != 4 || p.MinBackoff != 250*time.Millisecond || p.MaxBackoff != 10*time.Second {
+		t.Error("should retry up to four times with delays from 250ms to 10s", cross, p)
+	}
+	if DefaultRetryPolicy() == p {
+		t.Error("should return a new policy that callers can modify", cross)
+	}
+}
+
+func TestBackoffDefaults(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 5}
+
+	if d := p.backoff(1); d < defaultMinBackoff/2 || d > defaultMinBackoff {
+		t.Error("should use the default MinBackoff when unset", cross, d)
+	}
+	if d := p.backoff(20); d < defaultMaxBackoff/2 || d > defaultMaxBackoff {
+		t.Error("should use the default MaxBackoff when unset", cross, d)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+	if d := p.delay(1, resp); d != defaultMaxBackoff {
+		t.Error("should cap Retry-After at the default MaxBackoff", cross, d)
+	}
+}
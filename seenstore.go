@@ -0,0 +1,153 @@
+package starling
+
+import (
+	"container/list"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/astravexton/starling/internal/atomicfile"
+	"github.com/pkg/errors"
+)
+
+// SeenStore remembers the webhook events a WebhookHandler has handled so that redeliveries are
+// not handled twice. Implementations must be safe for concurrent use.
+type SeenStore interface {
+	Seen(uid string) (bool, error)            // Reports whether the event was marked and has not expired
+	Mark(uid string, expires time.Time) error // Remembers the event until expires
+}
+
+// seenEntry is an event remembered by a MemorySeenStore.
+type seenEntry struct {
+	uid     string
+	expires time.Time
+}
+
+// MemorySeenStore is a SeenStore that holds events in memory. Once full, the least recently
+// marked events are forgotten first.
+type MemorySeenStore struct {
+	capacity int
+
+	mu    sync.Mutex
+	order *list.List // Least recently marked at the back
+	index map[string]*list.Element
+}
+
+// NewMemorySeenStore returns a MemorySeenStore that remembers at most capacity events. The
+// capacity should comfortably exceed the number of events received during the retention window.
+func NewMemorySeenStore(capacity int) *MemorySeenStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemorySeenStore{
+		capacity: capacity,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+// Seen reports whether the event is remembered.
+func (s *MemorySeenStore) Seen(uid string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.index[uid]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(el.Value.(*seenEntry).expires) {
+		s.order.Remove(el)
+		delete(s.index, uid)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Mark remembers the event, forgetting the least recently marked if the store is full.
+func (s *MemorySeenStore) Mark(uid string, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.index[uid]; ok {
+		el.Value.(*seenEntry).expires = expires
+		s.order.MoveToFront(el)
+		return nil
+	}
+
+	s.index[uid] = s.order.PushFront(&seenEntry{uid: uid, expires: expires})
+	for s.order.Len() > s.capacity {
+		el := s.order.Back()
+		s.order.Remove(el)
+		delete(s.index, el.Value.(*seenEntry).uid)
+	}
+	return nil
+}
+
+// FileSeenStore is a SeenStore that keeps events as JSON in a single file, so that they are
+// remembered across restarts.
+type FileSeenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSeenStore returns a FileSeenStore that reads and writes events at path. The file is
+// created on the first Mark.
+func NewFileSeenStore(path string) *FileSeenStore {
+	return &FileSeenStore{path: path}
+}
+
+// Seen reports whether the event is recorded in the file and has not expired.
+func (s *FileSeenStore) Seen(uid string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	expires, ok := events[uid]
+	return ok && !time.Now().After(expires), nil
+}
+
+// Mark records the event in the file, dropping events that have expired.
+func (s *FileSeenStore) Mark(uid string, expires time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, err := s.read()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for k, exp := range events {
+		if now.After(exp) {
+			delete(events, k)
+		}
+	}
+	events[uid] = expires
+
+	b, err := json.Marshal(events)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode seen events")
+	}
+
+	return errors.Wrap(atomicfile.WriteFile(s.path, b), "unable to save seen events")
+}
+
+func (s *FileSeenStore) read() (map[string]time.Time, error) {
+	events := map[string]time.Time{}
+
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return events, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read seen events")
+	}
+	if err := json.Unmarshal(b, &events); err != nil {
+		return nil, errors.Wrap(err, "unable to parse seen events")
+	}
+	return events, nil
+}
@@ -0,0 +1,70 @@
+package starling
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testSeenStore(t *testing.T, s SeenStore) {
+	t.Helper()
+
+	seen, err := s.Seen("e1")
+	checkNoError(t, err)
+	if seen {
+		t.Error("should not have seen an unmarked event", cross)
+	}
+
+	checkNoError(t, s.Mark("e1", time.Now().Add(time.Hour)))
+	checkNoError(t, s.Mark("e2", time.Now().Add(-time.Second)))
+
+	if seen, _ := s.Seen("e1"); !seen {
+		t.Error("should have seen a marked event", cross)
+	}
+	if seen, _ := s.Seen("e2"); seen {
+		t.Error("should forget expired events", cross)
+	}
+}
+
+func TestMemorySeenStore(t *testing.T) {
+	testSeenStore(t, NewMemorySeenStore(10))
+}
+
+func TestMemorySeenStoreEviction(t *testing.T) {
+	s := NewMemorySeenStore(2)
+	expires := time.Now().Add(time.Hour)
+
+	s.Mark("e1", expires)
+	s.Mark("e2", expires)
+	s.Mark("e1", expires)
+	s.Mark("e3", expires)
+
+	if seen, _ := s.Seen("e2"); seen {
+		t.Error("should forget the least recently marked event", cross)
+	}
+	if seen, _ := s.Seen("e1"); !seen {
+		t.Error("should remember recently marked events", cross)
+	}
+	if seen, _ := s.Seen("e3"); !seen {
+		t.Error("should remember recently marked events", cross)
+	}
+}
+
+func TestFileSeenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "seenstore")
+	checkNoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "seen.json")
+	testSeenStore(t, NewFileSeenStore(path))
+
+	if seen, _ := NewFileSeenStore(path).Seen("e1"); !seen {
+		t.Error("should remember events across restarts", cross)
+	}
+
+	checkNoError(t, ioutil.WriteFile(path, []byte("[invalid]"), 0600))
+	_, err = NewFileSeenStore(path).Seen("e1")
+	checkHasError(t, err)
+}
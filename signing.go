@@ -0,0 +1,192 @@
+package starling
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// signatureDateFormat is the format of the Date header on signed requests.
+const signatureDateFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// SigningTransport is an http.RoundTripper that signs requests as required by
+// the Starling payment APIs. Selected requests are given a Date header, a Digest
+// header holding the SHA-512 digest of the body, and a signature over the request
+// target, Date and Digest that is appended to the Authorization header.
+//
+// The transport must sit beneath the one adding the access token so that the
+// signature can be appended to it. When using the golang.org/x/oauth2 library:
+//
+//	st, _ := starling.NewSigningTransport(keyUID, key, nil)
+//	tc := &http.Client{Transport: &oauth2.Transport{Source: ts, Base: st}}
+//	client := starling.NewClient(tc)
+type SigningTransport struct {
+	KeyUID string                   // UID of the public key registered with Starling
+	Key    crypto.Signer            // An *rsa.PrivateKey or *ecdsa.PrivateKey
+	Base   http.RoundTripper        // Transport used to send requests; http.DefaultTransport if nil
+	Match  func(*http.Request) bool // Selects the requests to sign; defaults to SignPaymentRequests
+
+	now func() time.Time
+}
+
+// NewSigningTransport returns a SigningTransport that signs payment requests
+// with the given key. An error is returned if the key type is not supported.
+func NewSigningTransport(keyUID string, key crypto.Signer, base http.RoundTripper) (*SigningTransport, error) {
+	if _, err := signatureAlgorithm(key); err != nil {
+		return nil, err
+	}
+	return &SigningTransport{KeyUID: keyUID, Key: key, Base: base}, nil
+}
+
+// SignPaymentRequests selects requests that initiate or modify payments, using either the v1 or
+// the v2 payments API.
+func SignPaymentRequests(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPut, http.MethodPost, http.MethodDelete:
+		return strings.HasPrefix(r.URL.Path, "/api/v1/payments/") ||
+			strings.HasPrefix(r.URL.Path, "/api/v2/payments/")
+	}
+	return false
+}
+
+// RoundTrip signs the request, if selected, and sends it using the base transport.
+func (t *SigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	match := t.Match
+	if match == nil {
+		match = SignPaymentRequests
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if !match(req) {
+		return base.RoundTrip(req)
+	}
+
+	signed, err := t.sign(req)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return base.RoundTrip(signed)
+}
+
+// sign returns a copy of the request carrying the Date, Digest and signed
+// Authorization headers. The original request is not modified.
+func (t *SigningTransport) sign(req *http.Request) (*http.Request, error) {
+	alg, err := signatureAlgorithm(t.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read body")
+		}
+	}
+
+	now := time.Now
+	if t.now != nil {
+		now = t.now
+	}
+
+	r := req.Clone(req.Context())
+	if req.Body != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	digest := sha512.Sum512(body)
+	r.Header.Set("Date", now().UTC().Format(signatureDateFormat))
+	r.Header.Set("Digest", base64.StdEncoding.EncodeToString(digest[:]))
+
+	sig, err := signMessage(t.Key, signingString(r))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sign request")
+	}
+
+	s := fmt.Sprintf(`Signature keyid="%s",algorithm="%s",headers="(request-target) Date Digest",signature="%s"`,
+		t.KeyUID, alg, base64.StdEncoding.EncodeToString(sig))
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		s = auth + ";" + s
+	}
+	r.Header.Set("Authorization", s)
+	return r, nil
+}
+
+// signingString returns the message covered by the request signature.
+func signingString(r *http.Request) string {
+	return "(request-target): " + strings.ToLower(r.Method) + " " + r.URL.RequestURI() + "\n" +
+		"Date: " + r.Header.Get("Date") + "\n" +
+		"Digest: " + r.Header.Get("Digest")
+}
+
+// signatureAlgorithm returns the name of the signature algorithm used for the key.
+func signatureAlgorithm(key crypto.Signer) (string, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return "rsa-sha512", nil
+	case *ecdsa.PrivateKey:
+		return "ecdsa-sha512", nil
+	}
+	return "", fmt.Errorf("unsupported signing key type %T", key)
+}
+
+// signMessage signs the SHA-512 digest of msg. RSA keys produce a PKCS #1 v1.5
+// signature and ECDSA keys an ASN.1 encoded signature.
+func signMessage(key crypto.Signer, msg string) ([]byte, error) {
+	digest := sha512.Sum512([]byte(msg))
+	return key.Sign(rand.Reader, digest[:], crypto.SHA512)
+}
+
+// ParseSigningKey parses a PEM encoded RSA or ECDSA private key in PKCS #1,
+// PKCS #8 or SEC 1 form.
+func ParseSigningKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse private key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+	if _, err := signatureAlgorithm(signer); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
@@ -0,0 +1,176 @@
+package starling
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var signatureHeader = regexp.MustCompile(`^Bearer token;Signature keyid="([^"]+)",algorithm="([^"]+)",headers="\(request-target\) Date Digest",signature="([^"]+)"$`)
+
+// bearerTransport adds a bearer token in the same way as oauth2.Transport.
+type bearerTransport struct {
+	base http.RoundTripper
+}
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer token")
+	return t.base.RoundTrip(r)
+}
+
+func TestSigningTransport(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		key    crypto.Signer
+		alg    string
+		verify func(digest, sig []byte) bool
+	}{
+		{
+			name: "rsa key",
+			key:  rsaKey,
+			alg:  "rsa-sha512",
+			verify: func(digest, sig []byte) bool {
+				return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA512, digest, sig) == nil
+			},
+		},
+		{
+			name: "ecdsa key",
+			key:  ecKey,
+			alg:  "ecdsa-sha512",
+			verify: func(digest, sig []byte) bool {
+				return ecdsa.VerifyASN1(&ecKey.PublicKey, digest, sig)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tc *testing.T) {
+			client, mux, _, teardown := setup()
+			defer teardown()
+
+			st, err := NewSigningTransport("b4d4e8a4-1f5c-4d3b-a6a4-3c8f1d5ad1b1", c.key, nil)
+			checkNoError(tc, err)
+			st.now = func() time.Time { return time.Date(2020, 9, 1, 10, 30, 0, 0, time.UTC) }
+			client.client = &http.Client{Transport: bearerTransport{base: st}}
+
+			path := "/api/v2/payments/local/account/a/category/c"
+			mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+				checkMethod(tc, r, http.MethodPut)
+				body, _ := ioutil.ReadAll(r.Body)
+
+				if got, want := r.Header.Get("Date"), "2020-09-01T10:30:00.000Z"; got != want {
+					tc.Error("should set the Date header", cross, got)
+				}
+
+				digest := sha512.Sum512(body)
+				if got, want := r.Header.Get("Digest"), base64.StdEncoding.EncodeToString(digest[:]); got != want {
+					tc.Error("should set the Digest header to the body digest", cross, got)
+				}
+
+				m := signatureHeader.FindStringSubmatch(r.Header.Get("Authorization"))
+				if m == nil {
+					tc.Fatal("should append the signature to the Authorization header", cross, r.Header.Get("Authorization"))
+				}
+				if m[1] != st.KeyUID || m[2] != c.alg {
+					tc.Error("should identify the key and algorithm", cross, m[1], m[2])
+				}
+
+				sig, _ := base64.StdEncoding.DecodeString(m[3])
+				msg := "(request-target): put " + path + "\nDate: " + r.Header.Get("Date") + "\nDigest: " + r.Header.Get("Digest")
+				sum := sha512.Sum512([]byte(msg))
+				if !c.verify(sum[:], sig) {
+					tc.Error("should produce a signature that verifies with the public key", cross)
+				}
+
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req, _ := client.NewRequest("PUT", "api/v2/payments/local/account/a/category/c", LocalPayment{Reference: "rent"})
+			_, err = client.Do(context.Background(), req, nil)
+			checkNoError(tc, err)
+		})
+	}
+}
+
+func TestSigningTransportSkipsUnmatched(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	st, _ := NewSigningTransport("key", key, nil)
+	client.client = &http.Client{Transport: st}
+
+	mux.HandleFunc("/api/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Digest") != "" || r.Header.Get("Authorization") != "" {
+			t.Error("should not sign requests that do not match", cross)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	_, _, err := client.Accounts(context.Background())
+	checkNoError(t, err)
+}
+
+func TestSigningTransportSignsV1Payments(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	st, _ := NewSigningTransport("key", key, nil)
+	client.client = &http.Client{Transport: st}
+
+	mux.HandleFunc("/api/v1/payments/local", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPost)
+		if r.Header.Get("Digest") == "" || r.Header.Get("Authorization") == "" {
+			t.Error("should sign v1 payment requests", cross)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	_, err := client.MakeLocalPayment(context.Background(), LocalPayment{Reference: "rent"})
+	checkNoError(t, err)
+}
+
+func TestParseSigningKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+
+	blocks := []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		{Type: "EC PRIVATE KEY", Bytes: ecDER},
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+	}
+	for _, b := range blocks {
+		if _, err := ParseSigningKey(pem.EncodeToMemory(b)); err != nil {
+			t.Error("should parse a", b.Type, cross, err)
+		}
+	}
+
+	if _, err := ParseSigningKey([]byte("not a key")); err == nil {
+		t.Error("should reject data that is not PEM encoded", cross)
+	}
+}
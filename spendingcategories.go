@@ -0,0 +1,139 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+)
+
+// SpendingCategory is the category associated with a transaction
+type SpendingCategory string
+
+// Spending categories for personal and joint accounts
+const (
+	SpendingCategoryBike                  SpendingCategory = "BIKE"
+	SpendingCategoryBillsAndServices      SpendingCategory = "BILLS_AND_SERVICES"
+	SpendingCategoryBucketList            SpendingCategory = "BUCKET_LIST"
+	SpendingCategoryCar                   SpendingCategory = "CAR"
+	SpendingCategoryCash                  SpendingCategory = "CASH"
+	SpendingCategoryCelebration           SpendingCategory = "CELEBRATION"
+	SpendingCategoryCharity               SpendingCategory = "CHARITY"
+	SpendingCategoryChildren              SpendingCategory = "CHILDREN"
+	SpendingCategoryClothes               SpendingCategory = "CLOTHES"
+	SpendingCategoryCoffee                SpendingCategory = "COFFEE"
+	SpendingCategoryDebtRepayment         SpendingCategory = "DEBT_REPAYMENT"
+	SpendingCategoryDIY                   SpendingCategory = "DIY"
+	SpendingCategoryDrinks                SpendingCategory = "DRINKS"
+	SpendingCategoryEatingOut             SpendingCategory = "EATING_OUT"
+	SpendingCategoryEducation             SpendingCategory = "EDUCATION"
+	SpendingCategoryEmergency             SpendingCategory = "EMERGENCY"
+	SpendingCategoryEntertainment         SpendingCategory = "ENTERTAINMENT"
+	SpendingCategoryEssentialSpend        SpendingCategory = "ESSENTIAL_SPEND"
+	SpendingCategoryExpenses              SpendingCategory = "EXPENSES"
+	SpendingCategoryFamily                SpendingCategory = "FAMILY"
+	SpendingCategoryFitness               SpendingCategory = "FITNESS"
+	SpendingCategoryFuel                  SpendingCategory = "FUEL"
+	SpendingCategoryGambling              SpendingCategory = "GAMBLING"
+	SpendingCategoryGaming                SpendingCategory = "GAMING"
+	SpendingCategoryGarden                SpendingCategory = "GARDEN"
+	SpendingCategoryGeneral               SpendingCategory = "GENERAL"
+	SpendingCategoryGifts                 SpendingCategory = "GIFTS"
+	SpendingCategoryGroceries             SpendingCategory = "GROCERIES"
+	SpendingCategoryHobbies               SpendingCategory = "HOBBIES"
+	SpendingCategoryHolidays              SpendingCategory = "HOLIDAYS"
+	SpendingCategoryHome                  SpendingCategory = "HOME"
+	SpendingCategoryImpulseBuy            SpendingCategory = "IMPULSE_BUY"
+	SpendingCategoryIncome                SpendingCategory = "INCOME"
+	SpendingCategoryInsurance             SpendingCategory = "INSURANCE"
+	SpendingCategoryInvestments           SpendingCategory = "INVESTMENTS"
+	SpendingCategoryLifestyle             SpendingCategory = "LIFESTYLE"
+	SpendingCategoryMaintenanceAndRepairs SpendingCategory = "MAINTENANCE_AND_REPAIRS"
+	SpendingCategoryMedical               SpendingCategory = "MEDICAL"
+	SpendingCategoryMortgage              SpendingCategory = "MORTGAGE"
+	SpendingCategoryNonEssentialSpend     SpendingCategory = "NON_ESSENTIAL_SPEND"
+	SpendingCategoryNone                  SpendingCategory = "NONE"
+	SpendingCategoryPayments              SpendingCategory = "PAYMENTS"
+	SpendingCategoryPersonalCare          SpendingCategory = "PERSONAL_CARE"
+	SpendingCategoryPersonalTransfers     SpendingCategory = "PERSONAL_TRANSFERS"
+	SpendingCategoryPets                  SpendingCategory = "PETS"
+	SpendingCategoryProjects              SpendingCategory = "PROJECTS"
+	SpendingCategoryRent                  SpendingCategory = "RENT"
+	SpendingCategorySaving                SpendingCategory = "SAVING"
+	SpendingCategoryShopping              SpendingCategory = "SHOPPING"
+	SpendingCategorySubscriptions         SpendingCategory = "SUBSCRIPTIONS"
+	SpendingCategoryTakeaway              SpendingCategory = "TAKEAWAY"
+	SpendingCategoryTaxi                  SpendingCategory = "TAXI"
+	SpendingCategoryTransport             SpendingCategory = "TRANSPORT"
+	SpendingCategoryTravel                SpendingCategory = "TRAVEL"
+	SpendingCategoryUtilities             SpendingCategory = "UTILITIES"
+	SpendingCategoryVehicles              SpendingCategory = "VEHICLES"
+	SpendingCategoryWedding               SpendingCategory = "WEDDING"
+	SpendingCategoryWellbeing             SpendingCategory = "WELLBEING"
+)
+
+// Spending categories for business accounts
+const (
+	SpendingCategoryAdmin                 SpendingCategory = "ADMIN"
+	SpendingCategoryBankCharges           SpendingCategory = "BANK_CHARGES"
+	SpendingCategoryBusinessEntertainment SpendingCategory = "BUSINESS_ENTERTAINMENT"
+	SpendingCategoryClientRefunds         SpendingCategory = "CLIENT_REFUNDS"
+	SpendingCategoryCorporationTax        SpendingCategory = "CORPORATION_TAX"
+	SpendingCategoryDirectorsWages        SpendingCategory = "DIRECTORS_WAGES"
+	SpendingCategoryDividends             SpendingCategory = "DIVIDENDS"
+	SpendingCategoryEquipment             SpendingCategory = "EQUIPMENT"
+	SpendingCategoryFoodAndDrink          SpendingCategory = "FOOD_AND_DRINK"
+	SpendingCategoryInterestPayments      SpendingCategory = "INTEREST_PAYMENTS"
+	SpendingCategoryInventory             SpendingCategory = "INVENTORY"
+	SpendingCategoryInvestmentCapital     SpendingCategory = "INVESTMENT_CAPITAL"
+	SpendingCategoryLoanPrincipal         SpendingCategory = "LOAN_PRINCIPAL"
+	SpendingCategoryMarketing             SpendingCategory = "MARKETING"
+	SpendingCategoryOther                 SpendingCategory = "OTHER"
+	SpendingCategoryOtherIncome           SpendingCategory = "OTHER_INCOME"
+	SpendingCategoryPersonal              SpendingCategory = "PERSONAL"
+	SpendingCategoryPhoneAndInternet      SpendingCategory = "PHONE_AND_INTERNET"
+	SpendingCategoryProfessionalServices  SpendingCategory = "PROFESSIONAL_SERVICES"
+	SpendingCategoryRepairsAndMaintenance SpendingCategory = "REPAIRS_AND_MAINTENANCE"
+	SpendingCategoryRevenue               SpendingCategory = "REVENUE"
+	SpendingCategorySelfAssessmentTax     SpendingCategory = "SELF_ASSESSMENT_TAX"
+	SpendingCategoryStaff                 SpendingCategory = "STAFF"
+	SpendingCategoryTransfers             SpendingCategory = "TRANSFERS"
+	SpendingCategoryVAT                   SpendingCategory = "VAT"
+	SpendingCategoryWorkplace             SpendingCategory = "WORKPLACE"
+)
+
+// SpendingCategoryUpdate is a request to change the spending category of a feed item
+type SpendingCategoryUpdate struct {
+	SpendingCategory SpendingCategory `json:"spendingCategory"`
+	Permanent        bool             `json:"permanentSpendingCategoryUpdate"`          // Also apply to future transactions with the counterparty
+	UpdatePrevious   bool             `json:"previousSpendingCategoryReferencesUpdate"` // Also apply to past transactions with the counterparty
+}
+
+// userNote is a request to change the note on a feed item
+type userNote struct {
+	UserNote string `json:"userNote"`
+}
+
+// SetFeedItemSpendingCategory changes the spending category of a feed item, optionally applying
+// it to past and future transactions with the same counterparty. It returns the http response in
+// case this is required for further processing. An error is returned on failure.
+func (c *Client) SetFeedItemSpendingCategory(ctx context.Context, act, cat, itm string, u SpendingCategoryUpdate) (*http.Response, error) {
+	req, err := c.NewRequest("PUT", "/api/v2/feed/account/"+act+"/category/"+cat+"/"+itm+"/spending-category", u)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(ctx, req, nil)
+	return resp, err
+}
+
+// SetFeedItemUserNote changes the note the customer has attached to a feed item. An empty note
+// removes it. It returns the http response in case this is required for further processing. An
+// error is returned on failure.
+func (c *Client) SetFeedItemUserNote(ctx context.Context, act, cat, itm, note string) (*http.Response, error) {
+	req, err := c.NewRequest("PUT", "/api/v2/feed/account/"+act+"/category/"+cat+"/"+itm+"/user-note", userNote{UserNote: note})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(ctx, req, nil)
+	return resp, err
+}
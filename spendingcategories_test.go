@@ -0,0 +1,70 @@
+package starling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestSetFeedItemSpendingCategory(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	update := SpendingCategoryUpdate{
+		SpendingCategory: SpendingCategoryGroceries,
+		Permanent:        true,
+		UpdatePrevious:   true,
+	}
+
+	mux.HandleFunc("/api/v2/feed/account/30aa7ab8-4389-4658-a4f8-0bc6d0015ba0/category/c423ab8d-9a6a-44b2-8db6-ac6000fe58e0/dbb59f1c-39e6-4558-87ba-11c142965393/spending-category", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+
+		var got map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal("should send a request that the API can parse", cross, err)
+		}
+		if got["spendingCategory"] != "GROCERIES" || got["permanentSpendingCategoryUpdate"] != true || got["previousSpendingCategoryReferencesUpdate"] != true {
+			t.Error("should send the spending category update", cross, got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := client.SetFeedItemSpendingCategory(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", "dbb59f1c-39e6-4558-87ba-11c142965393", update)
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusNoContent)
+}
+
+func TestSetFeedItemUserNote(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/feed/account/30aa7ab8-4389-4658-a4f8-0bc6d0015ba0/category/c423ab8d-9a6a-44b2-8db6-ac6000fe58e0/dbb59f1c-39e6-4558-87ba-11c142965393/user-note", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+
+		var got userNote
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.UserNote != "Team lunch" {
+			t.Error("should send the note", cross, got)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := client.SetFeedItemUserNote(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", "dbb59f1c-39e6-4558-87ba-11c142965393", "Team lunch")
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusNoContent)
+}
+
+func TestSetFeedItemSpendingCategoryNotFound(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/feed/account/30aa7ab8-4389-4658-a4f8-0bc6d0015ba0/category/c423ab8d-9a6a-44b2-8db6-ac6000fe58e0/unknown/spending-category", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := client.SetFeedItemSpendingCategory(context.Background(), "30aa7ab8-4389-4658-a4f8-0bc6d0015ba0", "c423ab8d-9a6a-44b2-8db6-ac6000fe58e0", "unknown", SpendingCategoryUpdate{SpendingCategory: SpendingCategoryNone})
+	if !IsNotFound(err) {
+		t.Error("should return a not found error", cross, err)
+	}
+}
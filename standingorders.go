@@ -0,0 +1,193 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// StandingOrderFrequency is how often a standing order is paid
+type StandingOrderFrequency string
+
+// Standing order frequencies
+const (
+	FrequencyDaily   StandingOrderFrequency = "DAILY"
+	FrequencyWeekly  StandingOrderFrequency = "WEEKLY"
+	FrequencyMonthly StandingOrderFrequency = "MONTHLY"
+	FrequencyYearly  StandingOrderFrequency = "YEARLY"
+)
+
+// StandingOrderRecurrence defines when a standing order is paid. A standing order is paid every
+// Interval periods of Frequency from StartDate. It ends after Count payments or on UntilDate,
+// whichever is set, and runs until cancelled if neither is. Dates are formatted as yyyy-mm-dd.
+type StandingOrderRecurrence struct {
+	StartDate string                 `json:"startDate"`
+	Frequency StandingOrderFrequency `json:"frequency"`
+	Interval  int32                  `json:"interval,omitempty"`
+	Count     int32                  `json:"count,omitempty"`
+	UntilDate string                 `json:"untilDate,omitempty"`
+}
+
+// StandingOrder is a recurring payment to a payee account
+type StandingOrder struct {
+	PaymentOrderUID  string                  `json:"paymentOrderUid"`
+	Amount           Amount                  `json:"amount"`
+	Reference        string                  `json:"reference"`
+	PayeeUID         string                  `json:"payeeUid"`
+	PayeeAccountUID  string                  `json:"payeeAccountUid"`
+	Recurrence       StandingOrderRecurrence `json:"standingOrderRecurrence"`
+	NextDate         string                  `json:"nextDate"`
+	CancelledAt      string                  `json:"cancelledAt"`
+	UpdatedAt        string                  `json:"updatedAt"`
+	SpendingCategory SpendingCategory        `json:"spendingCategory"`
+	CategoryUID      string                  `json:"categoryUid"`
+}
+
+// StandingOrderRequest is a request to create a standing order to a payee account
+type StandingOrderRequest struct {
+	ExternalIdentifier         string                  `json:"externalIdentifier"` // Caller supplied identifier used to detect duplicate requests
+	DestinationPayeeAccountUID string                  `json:"destinationPayeeAccountUid"`
+	Reference                  string                  `json:"reference"`
+	Amount                     Amount                  `json:"amount"`
+	Recurrence                 StandingOrderRecurrence `json:"standingOrderRecurrence"`
+	SpendingCategory           SpendingCategory        `json:"spendingCategory,omitempty"`
+}
+
+// StandingOrderUpdate is a request to change an existing standing order. The payee account of a
+// standing order cannot be changed.
+type StandingOrderUpdate struct {
+	ExternalIdentifier string                  `json:"externalIdentifier"`
+	Reference          string                  `json:"reference"`
+	Amount             Amount                  `json:"amount"`
+	Recurrence         StandingOrderRecurrence `json:"standingOrderRecurrence"`
+	SpendingCategory   SpendingCategory        `json:"spendingCategory,omitempty"`
+}
+
+// standingOrders is a list of standing orders
+type standingOrders struct {
+	StandingOrders []StandingOrder `json:"standingOrders"`
+}
+
+// nextPaymentDates is a list of the dates on which a standing order will next be paid
+type nextPaymentDates struct {
+	Dates []struct {
+		PaymentDate string `json:"paymentDate"`
+	} `json:"nextPaymentDatesList"`
+}
+
+// standingOrdersPath returns the path of the standing orders for an account and category.
+func standingOrdersPath(act, cat string) string {
+	return "/api/v2/payments/local/account/" + act + "/category/" + cat + "/standing-orders"
+}
+
+// StandingOrders returns the standing orders paid from an account and category. It also returns
+// the http response in case this is required for further processing. An error will be returned
+// if unable to retrieve the standing orders from the API.
+func (c *Client) StandingOrders(ctx context.Context, act, cat string) ([]StandingOrder, *http.Response, error) {
+	req, err := c.NewRequest("GET", standingOrdersPath(act, cat), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var so standingOrders
+	resp, err := c.Do(ctx, req, &so)
+	if err != nil {
+		return so.StandingOrders, resp, err
+	}
+
+	return so.StandingOrders, resp, nil
+}
+
+// StandingOrder returns a single standing order. It also returns the http response in case this
+// is required for further processing. An error will be returned if unable to retrieve the
+// standing order from the API.
+func (c *Client) StandingOrder(ctx context.Context, act, cat, uid string) (*StandingOrder, *http.Response, error) {
+	req, err := c.NewRequest("GET", standingOrdersPath(act, cat)+"/"+uid, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var so *StandingOrder
+	resp, err := c.Do(ctx, req, &so)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return so, resp, nil
+}
+
+// CreateStandingOrder creates a standing order paid from an account and category. It returns the
+// payment order UID of the standing order and the http response in case this is required for
+// further processing. An error will be returned if the API is unable to create the standing order.
+func (c *Client) CreateStandingOrder(ctx context.Context, act, cat string, so StandingOrderRequest) (*PaymentOrderConfirmation, *http.Response, error) {
+	req, err := c.NewRequest("PUT", standingOrdersPath(act, cat), so)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var poc *PaymentOrderConfirmation
+	resp, err := c.Do(ctx, req, &poc)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return poc, resp, nil
+}
+
+// UpdateStandingOrder changes the amount, reference or recurrence of a standing order without
+// cancelling it. It returns the payment order UID of the standing order and the http response in
+// case this is required for further processing. An error will be returned if the API is unable
+// to update the standing order.
+func (c *Client) UpdateStandingOrder(ctx context.Context, act, cat, uid string, so StandingOrderUpdate) (*PaymentOrderConfirmation, *http.Response, error) {
+	req, err := c.NewRequest("POST", standingOrdersPath(act, cat)+"/"+uid, so)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var poc *PaymentOrderConfirmation
+	resp, err := c.Do(ctx, req, &poc)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return poc, resp, nil
+}
+
+// CancelStandingOrder cancels a standing order so that no further payments are made. It returns
+// the http response in case this is required for further processing. An error is returned on
+// failure.
+func (c *Client) CancelStandingOrder(ctx context.Context, act, cat, uid string) (*http.Response, error) {
+	req, err := c.NewRequest("DELETE", standingOrdersPath(act, cat)+"/"+uid, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(ctx, req, nil)
+	return resp, err
+}
+
+// UpcomingStandingOrderPayments returns the dates, formatted as yyyy-mm-dd, of the next count
+// payments of a standing order. It also returns the http response in case this is required for
+// further processing. An error will be returned if unable to retrieve the dates from the API.
+func (c *Client) UpcomingStandingOrderPayments(ctx context.Context, act, cat, uid string, count int) ([]string, *http.Response, error) {
+	req, err := c.NewRequest("GET", standingOrdersPath(act, cat)+"/"+uid+"/upcoming-payments", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("count", strconv.Itoa(count))
+	req.URL.RawQuery = q.Encode()
+
+	var npd nextPaymentDates
+	resp, err := c.Do(ctx, req, &npd)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	dates := make([]string, len(npd.Dates))
+	for i, d := range npd.Dates {
+		dates[i] = d.PaymentDate
+	}
+	return dates, resp, nil
+}
@@ -0,0 +1,219 @@
+package starling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	soAccount  = "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6"
+	soCategory = "cc1e5b58-0d63-4d1e-9f7c-5f7b5b0d7e41"
+	soUID      = "b7c8d9e0-f1a2-4b3c-8d4e-5f6a7b8c9d0e"
+	soPath     = "/api/v2/payments/local/account/" + soAccount + "/category/" + soCategory + "/standing-orders"
+)
+
+var standingOrdersTestCases = []struct {
+	name string
+	mock string
+}{
+	{
+		name: "no standing orders",
+		mock: `{"standingOrders": []}`,
+	},
+	{
+		name: "monthly rent",
+		mock: `{
+			"standingOrders": [
+				{
+					"paymentOrderUid": "b7c8d9e0-f1a2-4b3c-8d4e-5f6a7b8c9d0e",
+					"amount": {"currency": "GBP", "minorUnits": 85000},
+					"reference": "RENT",
+					"payeeUid": "2e6b7a1c-bd4a-4b8f-9e5d-3c1f0a2b4d6e",
+					"payeeAccountUid": "9c4d2e1f-6a7b-4c8d-9e0f-1a2b3c4d5e6f",
+					"standingOrderRecurrence": {
+						"startDate": "2020-06-01",
+						"frequency": "MONTHLY",
+						"interval": 1
+					},
+					"nextDate": "2020-07-01",
+					"updatedAt": "2020-05-20T10:11:12.000Z",
+					"spendingCategory": "BILLS_AND_SERVICES",
+					"categoryUid": "cc1e5b58-0d63-4d1e-9f7c-5f7b5b0d7e41"
+				}
+			]
+		}`,
+	},
+}
+
+func TestStandingOrders(t *testing.T) {
+	for _, tc := range standingOrdersTestCases {
+		t.Run(tc.name, func(st *testing.T) {
+			testStandingOrders(st, tc.name, tc.mock)
+		})
+	}
+}
+
+func testStandingOrders(t *testing.T, name, mock string) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(soPath, func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, mock)
+	})
+
+	got, resp, err := client.StandingOrders(context.Background(), soAccount, soCategory)
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusOK)
+
+	want := &standingOrders{}
+	json.Unmarshal([]byte(mock), want)
+
+	if !reflect.DeepEqual(got, want.StandingOrders) {
+		t.Error("should return standing orders matching the mock response", cross)
+	}
+}
+
+func TestStandingOrder(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(soPath+"/"+soUID, func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"paymentOrderUid": "b7c8d9e0-f1a2-4b3c-8d4e-5f6a7b8c9d0e",
+			"amount": {"currency": "GBP", "minorUnits": 250000},
+			"reference": "PAYROLL",
+			"standingOrderRecurrence": {"startDate": "2020-06-26", "frequency": "MONTHLY", "count": 12}
+		}`)
+	})
+
+	got, _, err := client.StandingOrder(context.Background(), soAccount, soCategory, soUID)
+	checkNoError(t, err)
+
+	want := StandingOrderRecurrence{StartDate: "2020-06-26", Frequency: FrequencyMonthly, Count: 12}
+	if got == nil || got.Recurrence != want {
+		t.Error("should return the recurrence of the standing order", cross, got)
+	}
+}
+
+func TestStandingOrderNotFound(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(soPath+"/"+soUID, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	got, _, err := client.StandingOrder(context.Background(), soAccount, soCategory, soUID)
+	if !IsNotFound(err) {
+		t.Error("should return a not found error", cross, err)
+	}
+	if got != nil {
+		t.Error("should not return a standing order", cross)
+	}
+}
+
+func TestCreateStandingOrder(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	so := StandingOrderRequest{
+		ExternalIdentifier:         "rent",
+		DestinationPayeeAccountUID: "9c4d2e1f-6a7b-4c8d-9e0f-1a2b3c4d5e6f",
+		Reference:                  "RENT",
+		Amount:                     Amount{Currency: "GBP", MinorUnits: 85000},
+		Recurrence:                 StandingOrderRecurrence{StartDate: "2020-06-01", Frequency: FrequencyMonthly, Interval: 1},
+	}
+
+	mux.HandleFunc(soPath, func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPut)
+
+		var got StandingOrderRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal("should send a request that the API can parse", cross, err)
+		}
+		if !reflect.DeepEqual(got, so) {
+			t.Error("should send a standing order that matches the request", cross, got)
+		}
+
+		fmt.Fprint(w, `{"paymentOrderUid":"b7c8d9e0-f1a2-4b3c-8d4e-5f6a7b8c9d0e","consentInformation":{"approvalType":"NO_APPROVAL_REQUIRED"}}`)
+	})
+
+	got, _, err := client.CreateStandingOrder(context.Background(), soAccount, soCategory, so)
+	checkNoError(t, err)
+
+	if got == nil || got.PaymentOrderUID != soUID {
+		t.Error("should return the payment order UID of the standing order", cross, got)
+	}
+}
+
+func TestUpdateStandingOrder(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	update := StandingOrderUpdate{
+		ExternalIdentifier: "rent-increase",
+		Reference:          "RENT",
+		Amount:             Amount{Currency: "GBP", MinorUnits: 90000},
+		Recurrence:         StandingOrderRecurrence{StartDate: "2020-06-01", Frequency: FrequencyMonthly, Interval: 1},
+	}
+
+	mux.HandleFunc(soPath+"/"+soUID, func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodPost)
+
+		var got StandingOrderUpdate
+		json.NewDecoder(r.Body).Decode(&got)
+		if !reflect.DeepEqual(got, update) {
+			t.Error("should send an update that matches the request", cross, got)
+		}
+
+		fmt.Fprint(w, `{"paymentOrderUid":"b7c8d9e0-f1a2-4b3c-8d4e-5f6a7b8c9d0e","consentInformation":{"approvalType":"NO_APPROVAL_REQUIRED"}}`)
+	})
+
+	got, _, err := client.UpdateStandingOrder(context.Background(), soAccount, soCategory, soUID, update)
+	checkNoError(t, err)
+
+	if got == nil || got.PaymentOrderUID != soUID {
+		t.Error("should return the payment order UID of the standing order", cross, got)
+	}
+}
+
+func TestCancelStandingOrder(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(soPath+"/"+soUID, func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodDelete)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := client.CancelStandingOrder(context.Background(), soAccount, soCategory, soUID)
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusNoContent)
+}
+
+func TestUpcomingStandingOrderPayments(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(soPath+"/"+soUID+"/upcoming-payments", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		if got := r.URL.Query().Get("count"); got != "3" {
+			t.Error("should request the given number of payments", cross, got)
+		}
+		fmt.Fprint(w, `{"nextPaymentDatesList":[{"paymentDate":"2020-07-01"},{"paymentDate":"2020-08-01"},{"paymentDate":"2020-09-01"}]}`)
+	})
+
+	got, _, err := client.UpcomingStandingOrderPayments(context.Background(), soAccount, soCategory, soUID, 3)
+	checkNoError(t, err)
+
+	want := []string{"2020-07-01", "2020-08-01", "2020-09-01"}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("should return the upcoming payment dates", cross, got)
+	}
+}
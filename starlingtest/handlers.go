@@ -0,0 +1,626 @@
+package starlingtest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astravexton/starling"
+	"github.com/google/uuid"
+)
+
+func (s *Server) registerRoutes() {
+	s.handle("GET", "/api/v2/accounts", s.listAccounts)
+	s.handle("GET", "/api/v2/accounts/{account}/identifiers", s.accountIdentifiers)
+	s.handle("GET", "/api/v2/accounts/{account}/balance", s.accountBalance)
+
+	s.handle("GET", "/api/v2/feed/account/{account}/category/{category}", s.listFeed)
+	s.handle("GET", "/api/v2/feed/account/{account}/category/{category}/transactions-between", s.feedBetween)
+	s.handle("GET", "/api/v2/feed/account/{account}/category/{category}/{item}", s.getFeedItem)
+	s.handle("GET", "/api/v2/feed/account/{account}/settled-transactions-between", s.feedBetween)
+	s.handle("PUT", "/api/v2/feed/account/{account}/category/{category}/{item}/spending-category", s.setSpendingCategory)
+	s.handle("PUT", "/api/v2/feed/account/{account}/category/{category}/{item}/user-note", s.setUserNote)
+
+	s.handle("GET", "/api/v2/account/{account}/savings-goals", s.listSavingsGoals)
+	s.handle("GET", "/api/v2/account/{account}/savings-goals/{goal}", s.getSavingsGoal)
+	s.handle("PUT", "/api/v2/account/{account}/savings-goals/{goal}", s.createSavingsGoal)
+	s.handle("DELETE", "/api/v2/account/{account}/savings-goals/{goal}", s.deleteSavingsGoal)
+	s.handle("PUT", "/api/v2/account/{account}/savings-goals/{goal}/add-money/{transfer}", s.addMoney)
+	s.handle("PUT", "/api/v2/account/{account}/savings-goals/{goal}/withdraw-money/{transfer}", s.withdrawMoney)
+	s.handle("GET", "/api/v2/account/{account}/savings-goals/{goal}/photo", s.savingsGoalPhoto)
+	s.handle("GET", "/api/v2/account/{account}/savings-goals/{goal}/recurring-transfer", s.getRecurringTransfer)
+	s.handle("PUT", "/api/v2/account/{account}/savings-goals/{goal}/recurring-transfer", s.putRecurringTransfer)
+	s.handle("DELETE", "/api/v2/account/{account}/savings-goals/{goal}/recurring-transfer", s.deleteRecurringTransfer)
+
+	s.handle("GET", "/api/v2/cards", s.listCards)
+	s.handle("PUT", "/api/v2/cards/{card}/controls/{control}", s.cardControl)
+
+	s.handle("GET", "/api/v1/direct-debit/mandates", s.listMandates)
+	s.handle("GET", "/api/v1/direct-debit/mandates/{mandate}", s.getMandate)
+	s.handle("DELETE", "/api/v1/direct-debit/mandates/{mandate}", s.deleteMandate)
+
+	s.handle("POST", "/api/v1/payments/local", s.localPayment)
+	s.handle("POST", "/api/v1/payments/scheduled", s.createScheduledPayment)
+	s.handle("GET", "/api/v1/payments/scheduled", s.listScheduledPayments)
+}
+
+// lookupAccount returns the account named in the path, writing a 404 response
+// if it does not exist. The caller must hold s.mu.
+func (s *Server) lookupAccount(w http.ResponseWriter, p params) *account {
+	act := s.account(p["account"])
+	if act == nil {
+		writeErrors(w, http.StatusNotFound, "ACCOUNT_NOT_FOUND")
+	}
+	return act
+}
+
+// lookupGoal returns the account and savings goal named in the path, writing a
+// 404 response if either does not exist. The caller must hold s.mu.
+func (s *Server) lookupGoal(w http.ResponseWriter, p params) (*account, *goal) {
+	act := s.lookupAccount(w, p)
+	if act == nil {
+		return nil, nil
+	}
+	g := act.goal(p["goal"])
+	if g == nil {
+		writeErrors(w, http.StatusNotFound, "SAVINGS_GOAL_NOT_FOUND")
+	}
+	return act, g
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acts := []starling.AccountSummary{}
+	for _, a := range s.accounts {
+		acts = append(acts, a.summary)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"accounts": acts})
+}
+
+func (s *Server) accountIdentifiers(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if act := s.lookupAccount(w, p); act != nil {
+		writeJSON(w, http.StatusOK, act.id)
+	}
+}
+
+func (s *Server) accountBalance(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.lookupAccount(w, p)
+	if act == nil {
+		return
+	}
+
+	amt := func(v int64) starling.Amount { return starling.Amount{Currency: act.summary.Currency, MinorUnits: v} }
+	writeJSON(w, http.StatusOK, starling.Balance{
+		Cleared:     amt(act.balance),
+		Effective:   amt(act.balance),
+		PendingTxns: amt(0),
+		Overdraft:   amt(0),
+		Amount:      amt(act.balance),
+	})
+}
+
+func (s *Server) listFeed(w http.ResponseWriter, r *http.Request, p params) {
+	var since time.Time
+	if v := r.URL.Query().Get("changesSince"); v != "" {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, "INVALID_CHANGES_SINCE")
+			return
+		}
+		since = t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.lookupAccount(w, p)
+	if act == nil {
+		return
+	}
+
+	items := []starling.FeedItem{}
+	for _, it := range act.feed {
+		if it.CategoryUID == p["category"] && !it.UpdatedAt.Before(since) {
+			items = append(items, *it)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].TransactionTime.After(items[j].TransactionTime) })
+	writeJSON(w, http.StatusOK, map[string]interface{}{"feedItems": items})
+}
+
+// feedBetween lists the items transacted in a range, for a single category if the
+// path names one and otherwise the settled items across the account.
+func (s *Server) feedBetween(w http.ResponseWriter, r *http.Request, p params) {
+	q := r.URL.Query()
+	min, err := time.Parse(time.RFC3339Nano, q.Get("minTransactionTimestamp"))
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "INVALID_MIN_TRANSACTION_TIMESTAMP")
+		return
+	}
+	max, err := time.Parse(time.RFC3339Nano, q.Get("maxTransactionTimestamp"))
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "INVALID_MAX_TRANSACTION_TIMESTAMP")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.lookupAccount(w, p)
+	if act == nil {
+		return
+	}
+
+	cat, inCategory := p["category"]
+	items := []starling.FeedItem{}
+	for _, it := range act.feed {
+		if it.TransactionTime.Before(min) || it.TransactionTime.After(max) {
+			continue
+		}
+		if (inCategory && it.CategoryUID != cat) || (!inCategory && it.Status != "SETTLED") {
+			continue
+		}
+		items = append(items, *it)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].TransactionTime.After(items[j].TransactionTime) })
+	writeJSON(w, http.StatusOK, map[string]interface{}{"feedItems": items})
+}
+
+func (s *Server) getFeedItem(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if it := s.lookupFeedItem(w, p); it != nil {
+		writeJSON(w, http.StatusOK, it)
+	}
+}
+
+// lookupFeedItem returns the feed item named in the path, writing a 404 if
+// there is no such item. The caller must hold s.mu.
+func (s *Server) lookupFeedItem(w http.ResponseWriter, p params) *starling.FeedItem {
+	act := s.lookupAccount(w, p)
+	if act == nil {
+		return nil
+	}
+	for _, it := range act.feed {
+		if it.CategoryUID == p["category"] && it.FeedItemUID == p["item"] {
+			return it
+		}
+	}
+	writeErrors(w, http.StatusNotFound, "FEED_ITEM_NOT_FOUND")
+	return nil
+}
+
+func (s *Server) setSpendingCategory(w http.ResponseWriter, r *http.Request, p params) {
+	var u starling.SpendingCategoryUpdate
+	if !decodeBody(w, r, &u) {
+		return
+	}
+	if u.SpendingCategory == "" {
+		writeErrors(w, http.StatusBadRequest, "SPENDING_CATEGORY_REQUIRED")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it := s.lookupFeedItem(w, p)
+	if it == nil {
+		return
+	}
+	it.SpendingCategory = u.SpendingCategory
+	it.UpdatedAt = s.now()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) setUserNote(w http.ResponseWriter, r *http.Request, p params) {
+	var u struct {
+		UserNote string `json:"userNote"`
+	}
+	if !decodeBody(w, r, &u) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it := s.lookupFeedItem(w, p)
+	if it == nil {
+		return
+	}
+	it.UserNote = u.UserNote
+	it.UpdatedAt = s.now()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listSavingsGoals(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.lookupAccount(w, p)
+	if act == nil {
+		return
+	}
+	goals := []starling.SavingsGoal{}
+	for _, g := range act.goals {
+		goals = append(goals, g.SavingsGoal)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"savingsGoalList": goals})
+}
+
+func (s *Server) getSavingsGoal(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, g := s.lookupGoal(w, p); g != nil {
+		writeJSON(w, http.StatusOK, g.SavingsGoal)
+	}
+}
+
+func (s *Server) createSavingsGoal(w http.ResponseWriter, r *http.Request, p params) {
+	var req starling.SavingsGoalRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.lookupAccount(w, p)
+	if act == nil {
+		return
+	}
+	if req.Name == "" {
+		writeErrors(w, http.StatusBadRequest, "NAME_REQUIRED")
+		return
+	}
+
+	g := act.goal(p["goal"])
+	if g == nil {
+		g = &goal{}
+		g.UID = p["goal"]
+		g.TotalSaved = starling.Amount{Currency: req.Currency}
+		act.goals = append(act.goals, g)
+	}
+	g.Name = req.Name
+	g.Target = req.Target
+	g.photo = req.Base64EncodedPhoto
+	g.updatePercentage()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"savingsGoalUid": g.UID, "success": true, "errors": []string{}})
+}
+
+func (s *Server) deleteSavingsGoal(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act, g := s.lookupGoal(w, p)
+	if g == nil {
+		return
+	}
+
+	// Money left in the goal is returned to the account.
+	act.balance += g.TotalSaved.MinorUnits
+	for i, v := range act.goals {
+		if v == g {
+			act.goals = append(act.goals[:i], act.goals[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addMoney(w http.ResponseWriter, r *http.Request, p params) {
+	s.moveSavings(w, r, p, true)
+}
+
+func (s *Server) withdrawMoney(w http.ResponseWriter, r *http.Request, p params) {
+	s.moveSavings(w, r, p, false)
+}
+
+// moveSavings transfers money between an account and one of its savings goals,
+// recording the transfer in the feeds of both categories.
+func (s *Server) moveSavings(w http.ResponseWriter, r *http.Request, p params, in bool) {
+	var req struct {
+		Amount starling.Amount `json:"amount"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act, g := s.lookupGoal(w, p)
+	if g == nil {
+		return
+	}
+	if req.Amount.MinorUnits <= 0 {
+		writeErrors(w, http.StatusBadRequest, "INVALID_AMOUNT")
+		return
+	}
+	if req.Amount.Currency != act.summary.Currency {
+		writeErrors(w, http.StatusBadRequest, "INVALID_CURRENCY")
+		return
+	}
+
+	main, saved := "OUT", "IN"
+	from := &act.balance
+	if !in {
+		main, saved = "IN", "OUT"
+		from = &g.TotalSaved.MinorUnits
+	}
+	if *from < req.Amount.MinorUnits {
+		writeErrors(w, http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+		return
+	}
+
+	if in {
+		act.balance -= req.Amount.MinorUnits
+		g.TotalSaved.MinorUnits += req.Amount.MinorUnits
+	} else {
+		act.balance += req.Amount.MinorUnits
+		g.TotalSaved.MinorUnits -= req.Amount.MinorUnits
+	}
+	g.updatePercentage()
+
+	s.record(act, transfer{
+		category: act.summary.DefaultCategory, direction: main, amount: req.Amount, source: "INTERNAL_TRANSFER",
+		counterPartyType: "CATEGORY", counterPartyUID: g.UID, counterPartyName: g.Name,
+	})
+	s.record(act, transfer{
+		category: g.UID, direction: saved, amount: req.Amount, source: "INTERNAL_TRANSFER",
+		counterPartyType: "CATEGORY", counterPartyUID: act.summary.DefaultCategory, counterPartyName: act.name,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"transferUid": p["transfer"], "success": true, "errors": []string{}})
+}
+
+func (s *Server) savingsGoalPhoto(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, g := s.lookupGoal(w, p); g != nil {
+		writeJSON(w, http.StatusOK, starling.Photo{Base64EncodedPhoto: g.photo})
+	}
+}
+
+func (s *Server) getRecurringTransfer(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, g := s.lookupGoal(w, p)
+	if g == nil {
+		return
+	}
+	if g.recurring == nil {
+		writeErrors(w, http.StatusNotFound, "RECURRING_TRANSFER_NOT_FOUND")
+		return
+	}
+	writeJSON(w, http.StatusOK, g.recurring)
+}
+
+func (s *Server) putRecurringTransfer(w http.ResponseWriter, r *http.Request, p params) {
+	var req starling.RecurringTransferRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, g := s.lookupGoal(w, p)
+	if g == nil {
+		return
+	}
+	if req.UID == "" {
+		req.UID = uuid.New().String()
+	}
+	g.recurring = &req
+	writeJSON(w, http.StatusOK, map[string]interface{}{"transferUid": req.UID, "success": true, "errors": []string{}})
+}
+
+func (s *Server) deleteRecurringTransfer(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, g := s.lookupGoal(w, p); g != nil {
+		g.recurring = nil
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) listCards(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cards := []starling.Card{}
+	for _, c := range s.cards {
+		cards = append(cards, *c)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"cards": cards})
+}
+
+func (s *Server) cardControl(w http.ResponseWriter, r *http.Request, p params) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var card *starling.Card
+	for _, c := range s.cards {
+		if c.CardUID == p["card"] {
+			card = c
+		}
+	}
+	if card == nil {
+		writeErrors(w, http.StatusNotFound, "CARD_NOT_FOUND")
+		return
+	}
+
+	controls := map[string]*bool{
+		"enabled":               &card.Enabled,
+		"atm-enabled":           &card.AtmEnabled,
+		"gambling-enabled":      &card.GamblingEnabled,
+		"mag-stripe-enabled":    &card.MagStripeEnabled,
+		"mobile-wallet-enabled": &card.MobileWalletEnabled,
+		"online-enabled":        &card.OnlineEnabled,
+		"pos-enabled":           &card.PosEnabled,
+	}
+	flag, ok := controls[strings.ToLower(p["control"])]
+	if !ok {
+		writeErrors(w, http.StatusNotFound, "UNKNOWN_CONTROL")
+		return
+	}
+	*flag = req.Enabled
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listMandates(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := []starling.DirectDebitMandate{}
+	for _, m := range s.mandates {
+		ms = append(ms, *m)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_links":    map[string]interface{}{},
+		"_embedded": map[string]interface{}{"mandates": ms},
+	})
+}
+
+func (s *Server) getMandate(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.mandates {
+		if m.UID == p["mandate"] {
+			writeJSON(w, http.StatusOK, m)
+			return
+		}
+	}
+	writeErrors(w, http.StatusNotFound, "MANDATE_NOT_FOUND")
+}
+
+func (s *Server) deleteMandate(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.mandates {
+		if m.UID == p["mandate"] {
+			s.mandates = append(s.mandates[:i], s.mandates[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeErrors(w, http.StatusNotFound, "MANDATE_NOT_FOUND")
+}
+
+// localPayment pays from the first account on the server. If the destination is
+// another account on the server it is credited, otherwise the money leaves the
+// fake bank.
+func (s *Server) localPayment(w http.ResponseWriter, r *http.Request, p params) {
+	var req starling.LocalPayment
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.accounts) == 0 {
+		writeErrors(w, http.StatusBadRequest, "ACCOUNT_NOT_FOUND")
+		return
+	}
+	src := s.accounts[0]
+
+	amount := starling.Amount{Currency: req.Payment.Currency, MinorUnits: minorUnits(req.Payment.Amount)}
+	if amount.MinorUnits <= 0 {
+		writeErrors(w, http.StatusBadRequest, "INVALID_AMOUNT")
+		return
+	}
+	if amount.Currency != src.summary.Currency {
+		writeErrors(w, http.StatusBadRequest, "INVALID_CURRENCY")
+		return
+	}
+	if src.balance < amount.MinorUnits {
+		writeErrors(w, http.StatusBadRequest, "INSUFFICIENT_FUNDS")
+		return
+	}
+
+	dst := s.account(req.DestinationAccountUID)
+	name := ""
+	if dst != nil {
+		name = dst.name
+	}
+
+	src.balance -= amount.MinorUnits
+	s.record(src, transfer{
+		category: src.summary.DefaultCategory, direction: "OUT", amount: amount, source: "FASTER_PAYMENTS_OUT",
+		counterPartyType: "PAYEE", counterPartyUID: req.DestinationAccountUID, counterPartyName: name, reference: req.Reference,
+	})
+
+	if dst != nil {
+		dst.balance += amount.MinorUnits
+		s.record(dst, transfer{
+			category: dst.summary.DefaultCategory, direction: "IN", amount: amount, source: "FASTER_PAYMENTS_IN",
+			counterPartyType: "SENDER", counterPartyUID: src.summary.UID, counterPartyName: src.name, reference: req.Reference,
+		})
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) createScheduledPayment(w http.ResponseWriter, r *http.Request, p params) {
+	var req starling.ScheduledPayment
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	po := &starling.PaymentOrder{
+		UID:                        uuid.New().String(),
+		Currency:                   req.Payment.Currency,
+		Amount:                     req.Payment.Amount,
+		Reference:                  req.Reference,
+		ReceivingContactAccountUID: req.DestinationAccountUID,
+		RecurrenceRule:             req.Schedule,
+		StartDate:                  req.Schedule.StartDate,
+		NextDate:                   req.Schedule.StartDate,
+		PaymentType:                "STANDING_ORDER",
+	}
+	s.scheduled = append(s.scheduled, po)
+
+	w.Header().Set("Location", "/api/v1/payments/scheduled/"+po.UID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) listScheduledPayments(w http.ResponseWriter, r *http.Request, p params) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pos := []starling.PaymentOrder{}
+	for _, po := range s.scheduled {
+		pos = append(pos, *po)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_embedded": map[string]interface{}{"paymentOrders": pos},
+	})
+}
@@ -0,0 +1,307 @@
+package starlingtest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/astravexton/starling"
+	"github.com/google/uuid"
+)
+
+// account holds the state of a single fake account.
+type account struct {
+	summary starling.AccountSummary
+	id      starling.AccountID
+	name    string
+	balance int64
+	goals   []*goal
+	feed    []*starling.FeedItem
+}
+
+// goal holds the state of a savings goal. Savings goals are categories of the
+// account they belong to.
+type goal struct {
+	starling.SavingsGoal
+	photo     string
+	recurring *starling.RecurringTransferRequest
+}
+
+// Account describes an account to seed the server with. Fields left empty are
+// given generated values.
+type Account struct {
+	UID             string
+	DefaultCategory string
+	Name            string
+	Currency        string // Defaults to GBP
+	AccountNumber   string
+	SortCode        string
+	Balance         int64 // Opening balance in minor units
+}
+
+// AddAccount adds an account to the server and returns its summary.
+func (s *Server) AddAccount(a Account) starling.AccountSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a.UID == "" {
+		a.UID = uuid.New().String()
+	}
+	if a.DefaultCategory == "" {
+		a.DefaultCategory = uuid.New().String()
+	}
+	if a.Currency == "" {
+		a.Currency = "GBP"
+	}
+	if a.Name == "" {
+		a.Name = "Personal"
+	}
+	if a.AccountNumber == "" {
+		a.AccountNumber = fmt.Sprintf("%08d", rand.Intn(100000000))
+	}
+	if a.SortCode == "" {
+		a.SortCode = "608371"
+	}
+
+	act := &account{
+		summary: starling.AccountSummary{
+			UID:             a.UID,
+			DefaultCategory: a.DefaultCategory,
+			Currency:        a.Currency,
+			CreatedAt:       s.now().Format(time.RFC3339Nano),
+		},
+		id: starling.AccountID{
+			ID:     a.AccountNumber,
+			BankID: a.SortCode,
+			IBAN:   "GB00SRLG" + a.SortCode + a.AccountNumber,
+			BIC:    "SRLGGB2L",
+		},
+		name:    a.Name,
+		balance: a.Balance,
+	}
+	s.accounts = append(s.accounts, act)
+	return act.summary
+}
+
+// SetBalance sets the balance of an account in minor units.
+func (s *Server) SetBalance(accountUID string, minorUnits int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if act := s.account(accountUID); act != nil {
+		act.balance = minorUnits
+	}
+}
+
+// Balance returns the balance of an account in minor units, excluding money held
+// in savings goals.
+func (s *Server) Balance(accountUID string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if act := s.account(accountUID); act != nil {
+		return act.balance
+	}
+	return 0
+}
+
+// AddSavingsGoal adds a savings goal to an account and returns it. A UID is
+// generated if one is not provided. The goal's TotalSaved is not deducted from
+// the account balance.
+func (s *Server) AddSavingsGoal(accountUID string, g starling.SavingsGoal) starling.SavingsGoal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.account(accountUID)
+	if act == nil {
+		panic("starlingtest: unknown account " + accountUID)
+	}
+	if g.UID == "" {
+		g.UID = uuid.New().String()
+	}
+	if g.TotalSaved.Currency == "" {
+		g.TotalSaved.Currency = act.summary.Currency
+	}
+	gl := &goal{SavingsGoal: g}
+	gl.updatePercentage()
+	act.goals = append(act.goals, gl)
+	return gl.SavingsGoal
+}
+
+// AddFeedItem adds an item to the feed of an account. The item is placed in the
+// default category unless CategoryUID is set. Missing UIDs and timestamps are
+// generated. Adding a feed item does not change the account balance.
+func (s *Server) AddFeedItem(accountUID string, item starling.FeedItem) starling.FeedItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.account(accountUID)
+	if act == nil {
+		panic("starlingtest: unknown account " + accountUID)
+	}
+	if item.FeedItemUID == "" {
+		item.FeedItemUID = uuid.New().String()
+	}
+	if item.CategoryUID == "" {
+		item.CategoryUID = act.summary.DefaultCategory
+	}
+	item.AccountUID = accountUID
+	if item.TransactionTime.IsZero() {
+		item.TransactionTime = s.now()
+	}
+	if item.UpdatedAt.IsZero() {
+		item.UpdatedAt = item.TransactionTime
+	}
+	if item.Status == "" {
+		item.Status = "SETTLED"
+	}
+	act.feed = append(act.feed, &item)
+	return item
+}
+
+// FeedItems returns the feed items in a category of an account, ordered by
+// transaction time.
+func (s *Server) FeedItems(accountUID, categoryUID string) []starling.FeedItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	act := s.account(accountUID)
+	if act == nil {
+		return nil
+	}
+
+	var items []starling.FeedItem
+	for _, it := range act.feed {
+		if it.CategoryUID == categoryUID {
+			items = append(items, *it)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].TransactionTime.Before(items[j].TransactionTime) })
+	return items
+}
+
+// AddCard adds a card to the server and returns it. A UID is generated if one is
+// not provided.
+func (s *Server) AddCard(c starling.Card) starling.Card {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.CardUID == "" {
+		c.CardUID = uuid.New().String()
+	}
+	s.cards = append(s.cards, &c)
+	return c
+}
+
+// Card returns the current state of a card.
+func (s *Server) Card(cardUID string) (starling.Card, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.cards {
+		if c.CardUID == cardUID {
+			return *c, true
+		}
+	}
+	return starling.Card{}, false
+}
+
+// AddDirectDebitMandate adds a direct debit mandate to the server and returns it.
+// A UID is generated if one is not provided.
+func (s *Server) AddDirectDebitMandate(m starling.DirectDebitMandate) starling.DirectDebitMandate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m.UID == "" {
+		m.UID = uuid.New().String()
+	}
+	if m.Status == "" {
+		m.Status = "LIVE"
+	}
+	s.mandates = append(s.mandates, &m)
+	return m
+}
+
+// DirectDebitMandates returns the direct debit mandates held by the server.
+func (s *Server) DirectDebitMandates() []starling.DirectDebitMandate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := make([]starling.DirectDebitMandate, len(s.mandates))
+	for i, m := range s.mandates {
+		ms[i] = *m
+	}
+	return ms
+}
+
+// account returns the account with the given UID. The caller must hold s.mu.
+func (s *Server) account(uid string) *account {
+	for _, a := range s.accounts {
+		if a.summary.UID == uid {
+			return a
+		}
+	}
+	return nil
+}
+
+// goal returns the savings goal with the given UID.
+func (a *account) goal(uid string) *goal {
+	for _, g := range a.goals {
+		if g.UID == uid {
+			return g
+		}
+	}
+	return nil
+}
+
+func (g *goal) updatePercentage() {
+	g.SavedPercentage = 0
+	if g.Target.MinorUnits > 0 {
+		g.SavedPercentage = int32(g.TotalSaved.MinorUnits * 100 / g.Target.MinorUnits)
+	}
+}
+
+// transfer describes one side of a movement of money recorded in a feed.
+type transfer struct {
+	category         string
+	direction        string
+	amount           starling.Amount
+	source           string
+	counterPartyType string
+	counterPartyUID  string
+	counterPartyName string
+	reference        string
+}
+
+// record appends a settled feed item for the transfer. The caller must hold s.mu.
+func (s *Server) record(act *account, t transfer) *starling.FeedItem {
+	now := s.now()
+	item := &starling.FeedItem{
+		FeedItemUID:      uuid.New().String(),
+		CategoryUID:      t.category,
+		AccountUID:       act.summary.UID,
+		Amount:           t.amount,
+		SourceAmount:     t.amount,
+		Direction:        t.direction,
+		UpdatedAt:        now,
+		TransactionTime:  now,
+		SettlementTime:   now,
+		Source:           t.source,
+		Status:           "SETTLED",
+		CounterPartyType: t.counterPartyType,
+		CounterPartyUID:  t.counterPartyUID,
+		CounterPartyName: t.counterPartyName,
+		Reference:        t.reference,
+		Country:          "GB",
+	}
+	act.feed = append(act.feed, item)
+	return item
+}
+
+// minorUnits converts an amount in major units to minor units of a currency
+// with two decimal places.
+func minorUnits(major float64) int64 {
+	return int64(math.Round(major * 100))
+}
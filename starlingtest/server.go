@@ -0,0 +1,242 @@
+/*
+Package starlingtest provides an in-memory fake of the Starling API for use in
+tests.
+
+The fake is stateful: transfers and payments move money between balances and
+create the matching feed items, so code under test observes the same effects
+it would against the real API. Seed the server with accounts and other
+resources, then point a client at it:
+
+	srv := starlingtest.NewServer()
+	defer srv.Close()
+
+	act := srv.AddAccount(starlingtest.Account{Balance: 10000})
+	client := srv.Client()
+
+Faults can be injected to exercise error handling:
+
+	srv.InjectFault(starlingtest.Fault{Path: "/api/v2/accounts", Status: http.StatusTooManyRequests, Times: 1})
+
+Webhook handlers can be tested with payloads built by CardPurchase,
+FasterPaymentIn, DirectDebit and Reversal, signed by a WebhookSigner.
+*/
+package starlingtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astravexton/starling"
+)
+
+// Server is a fake Starling API server. It is safe for concurrent use.
+type Server struct {
+	URL string // Base URL of the server, without a trailing slash
+
+	srv    *httptest.Server
+	routes []route
+
+	mu        sync.Mutex
+	accounts  []*account
+	cards     []*starling.Card
+	mandates  []*starling.DirectDebitMandate
+	scheduled []*starling.PaymentOrder
+	faults    []*Fault
+	now       func() time.Time
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when
+// finished.
+func NewServer() *Server {
+	s := &Server{now: func() time.Time { return time.Now().UTC() }}
+	s.registerRoutes()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a starling.Client configured to send requests to the server.
+func (s *Server) Client() *starling.Client {
+	return s.ClientWithOptions(starling.ClientOptions{})
+}
+
+// ClientWithOptions returns a starling.Client configured to send requests to the
+// server. The BaseURL in opts is ignored.
+func (s *Server) ClientWithOptions(opts starling.ClientOptions) *starling.Client {
+	opts.BaseURL, _ = url.Parse(s.URL + "/")
+	return starling.NewClientWithOptions(s.srv.Client(), opts)
+}
+
+// Fault describes a failure the server should return instead of handling a request.
+type Fault struct {
+	Method     string        // HTTP method to match; empty matches any method
+	Path       string        // Path prefix to match; empty matches any path
+	Status     int           // HTTP status code to return
+	Times      int           // Number of requests to fail; zero fails every request until cleared
+	RetryAfter time.Duration // If non-zero, sent as the Retry-After header
+}
+
+// InjectFault makes the server fail matching requests. Faults are checked in the
+// order they were injected.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// FailNext makes the next n requests to any endpoint fail with status.
+func (s *Server) FailNext(status, n int) {
+	s.InjectFault(Fault{Status: status, Times: n})
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// fault returns the first fault matching the request, consuming one of its uses.
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// route maps a method and path pattern to a handler. Pattern segments written
+// as {name} match any single path segment.
+type route struct {
+	method  string
+	pattern []string
+	handler func(w http.ResponseWriter, r *http.Request, p params)
+}
+
+// params holds the values of the wildcard segments of a matched route.
+type params map[string]string
+
+func (s *Server) handle(method, pattern string, h func(w http.ResponseWriter, r *http.Request, p params)) {
+	s.routes = append(s.routes, route{method: method, pattern: strings.Split(strings.Trim(pattern, "/"), "/"), handler: h})
+}
+
+func (rt route) match(segs []string) (params, bool) {
+	if len(segs) != len(rt.pattern) {
+		return nil, false
+	}
+	p := params{}
+	for i, seg := range rt.pattern {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			p[seg[1:len(seg)-1]] = segs[i]
+			continue
+		}
+		if seg != segs[i] {
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f := s.fault(r); f != nil {
+		writeFault(w, f)
+		return
+	}
+
+	segs := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	methodAllowed := true
+	for _, rt := range s.routes {
+		p, ok := rt.match(segs)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodAllowed = false
+			continue
+		}
+		rt.handler(w, r, p)
+		return
+	}
+
+	if !methodAllowed {
+		writeErrors(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED")
+		return
+	}
+	writeErrors(w, http.StatusNotFound, "NOT_FOUND")
+}
+
+func writeFault(w http.ResponseWriter, f *Fault) {
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((f.RetryAfter+time.Second-1)/time.Second)))
+	}
+
+	switch f.Status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		writeJSON(w, f.Status, map[string]string{
+			"error":             "invalid_token",
+			"error_description": "Could not validate provided access token",
+		})
+	default:
+		writeErrors(w, f.Status, strings.ToUpper(strings.Replace(http.StatusText(f.Status), " ", "_", -1)))
+	}
+}
+
+// writeJSON writes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeErrors writes a response using the standard Starling error structure.
+func writeErrors(w http.ResponseWriter, status int, msgs ...string) {
+	type errorDetail struct {
+		Message string `json:"message"`
+	}
+	body := struct {
+		Errors  []errorDetail `json:"errors"`
+		Success bool          `json:"success"`
+	}{Errors: []errorDetail{}}
+	for _, m := range msgs {
+		body.Errors = append(body.Errors, errorDetail{Message: m})
+	}
+	writeJSON(w, status, body)
+}
+
+// decodeBody decodes the JSON request body into v, writing a 400 response if
+// the body cannot be parsed.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("INVALID_REQUEST: %v", err))
+		return false
+	}
+	return true
+}
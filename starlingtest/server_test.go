@@ -0,0 +1,370 @@
+package starlingtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/astravexton/starling"
+	"github.com/pkg/errors"
+)
+
+const cross = "✗"
+
+func TestAccounts(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	act := srv.AddAccount(Account{Balance: 12345, AccountNumber: "12345678", SortCode: "608371"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	acts, _, err := client.Accounts(ctx)
+	if err != nil || len(acts) != 1 || acts[0] != act {
+		t.Fatal("should list the seeded account", cross, acts, err)
+	}
+
+	id, _, err := client.AccountID(ctx, act.UID)
+	if err != nil || id.ID != "12345678" || id.BankID != "608371" {
+		t.Error("should return the account identifiers", cross, id, err)
+	}
+
+	bal, _, err := client.AccountBalance(ctx, act.UID)
+	if err != nil || bal.Effective.MinorUnits != 12345 || bal.Effective.Currency != "GBP" {
+		t.Error("should return the account balance", cross, bal, err)
+	}
+
+	srv.SetBalance(act.UID, 500)
+	bal, _, err = client.AccountBalance(ctx, act.UID)
+	if err != nil || bal.Effective.MinorUnits != 500 {
+		t.Error("should return the balance that was set", cross, bal, err)
+	}
+
+	_, _, err = client.AccountBalance(ctx, "unknown")
+	if !starling.IsNotFound(err) {
+		t.Error("should return not found for an unknown account", cross, err)
+	}
+}
+
+func TestFeedBetween(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	act := srv.AddAccount(Account{})
+	june := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	srv.AddFeedItem(act.UID, starling.FeedItem{TransactionTime: june.AddDate(0, 0, 2), Status: "SETTLED"})
+	srv.AddFeedItem(act.UID, starling.FeedItem{TransactionTime: june.AddDate(0, 0, 3), Status: "PENDING"})
+	srv.AddFeedItem(act.UID, starling.FeedItem{TransactionTime: june.AddDate(0, 1, 2), Status: "SETTLED"})
+
+	client := srv.Client()
+	dr := starling.DateRange{From: june, To: june.AddDate(0, 1, 0)}
+
+	items, _, err := client.FeedBetween(context.Background(), act.UID, act.DefaultCategory, dr)
+	if err != nil || len(items) != 2 {
+		t.Error("should return the items transacted in the range", cross, len(items), err)
+	}
+
+	items, _, err = client.SettledFeedBetween(context.Background(), act.UID, dr)
+	if err != nil || len(items) != 1 {
+		t.Error("should return the settled items transacted in the range", cross, len(items), err)
+	}
+}
+
+func TestFeedItemUpdates(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	act := srv.AddAccount(Account{})
+	it := srv.AddFeedItem(act.UID, starling.FeedItem{SpendingCategory: starling.SpendingCategoryGeneral})
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	_, err := client.SetFeedItemSpendingCategory(ctx, act.UID, act.DefaultCategory, it.FeedItemUID, starling.SpendingCategoryUpdate{SpendingCategory: starling.SpendingCategoryGroceries})
+	if err != nil {
+		t.Fatal("should set the spending category", cross, err)
+	}
+	_, err = client.SetFeedItemUserNote(ctx, act.UID, act.DefaultCategory, it.FeedItemUID, "Weekly shop")
+	if err != nil {
+		t.Fatal("should set the user note", cross, err)
+	}
+
+	got, _, err := client.FeedItem(ctx, act.UID, act.DefaultCategory, it.FeedItemUID)
+	if err != nil || got.SpendingCategory != starling.SpendingCategoryGroceries || got.UserNote != "Weekly shop" {
+		t.Error("should return the updated feed item", cross, got, err)
+	}
+}
+
+func TestSavingsGoalTransfers(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	act := srv.AddAccount(Account{Balance: 10000})
+	goal := srv.AddSavingsGoal(act.UID, starling.SavingsGoal{Name: "Holiday", Target: starling.Amount{Currency: "GBP", MinorUnits: 5000}})
+	client := srv.Client()
+	ctx := context.Background()
+
+	_, _, err := client.TransferToSavingsGoal(ctx, act.UID, goal.UID, starling.Amount{Currency: "GBP", MinorUnits: 2500})
+	if err != nil {
+		t.Fatal("should transfer into the savings goal", cross, err)
+	}
+
+	if got := srv.Balance(act.UID); got != 7500 {
+		t.Error("should debit the account", cross, got)
+	}
+
+	g, _, err := client.SavingsGoal(ctx, act.UID, goal.UID)
+	if err != nil || g.TotalSaved.MinorUnits != 2500 || g.SavedPercentage != 50 {
+		t.Error("should credit the savings goal", cross, g, err)
+	}
+
+	out, _, err := client.Feed(ctx, act.UID, act.DefaultCategory, time.Time{})
+	if err != nil || len(out) != 1 || out[0].Direction != "OUT" || out[0].Amount.MinorUnits != 2500 {
+		t.Error("should record the transfer in the account feed", cross, out, err)
+	}
+
+	in, _, err := client.Feed(ctx, act.UID, goal.UID, time.Time{})
+	if err != nil || len(in) != 1 || in[0].Direction != "IN" {
+		t.Error("should record the transfer in the savings goal feed", cross, in, err)
+	}
+
+	_, _, err = client.TransferFromSavingsGoal(ctx, act.UID, goal.UID, starling.Amount{Currency: "GBP", MinorUnits: 5000})
+	if !starling.IsInsufficientFunds(err) {
+		t.Error("should refuse to withdraw more than has been saved", cross, err)
+	}
+}
+
+func TestSavingsGoals(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	act := srv.AddAccount(Account{Balance: 1000})
+	client := srv.Client()
+	ctx := context.Background()
+
+	const uid = "0c4e1d2a-3b5f-4a6c-8d7e-9f0a1b2c3d4e"
+	_, err := client.CreateSavingsGoal(ctx, act.UID, uid, starling.SavingsGoalRequest{
+		Name:               "Holiday",
+		Currency:           "GBP",
+		Target:             starling.Amount{Currency: "GBP", MinorUnits: 5000},
+		Base64EncodedPhoto: "cGhvdG8=",
+	})
+	if err != nil {
+		t.Fatal("should create the savings goal", cross, err)
+	}
+	if _, err := client.CreateSavingsGoal(ctx, act.UID, uid, starling.SavingsGoalRequest{}); err == nil {
+		t.Error("should refuse a savings goal without a name", cross)
+	}
+
+	goals, _, err := client.SavingsGoals(ctx, act.UID)
+	if err != nil || len(goals) != 1 || goals[0].UID != uid || goals[0].Name != "Holiday" {
+		t.Fatal("should list the created savings goal", cross, goals, err)
+	}
+
+	photo, _, err := client.SavingsGoalPhoto(ctx, act.UID, uid)
+	if err != nil || photo.Base64EncodedPhoto != "cGhvdG8=" {
+		t.Error("should return the savings goal photo", cross, photo, err)
+	}
+
+	if _, _, err := client.RecurringTransfer(ctx, act.UID, uid); !starling.IsNotFound(err) {
+		t.Error("should return not found before a recurring transfer is set", cross, err)
+	}
+	rtr := starling.RecurringTransferRequest{
+		RecurrenceRule: starling.RecurrenceRule{StartDate: "2020-06-01", Frequency: "MONTHLY"},
+		Amount:         starling.Amount{Currency: "GBP", MinorUnits: 500},
+	}
+	if _, _, err := client.CreateRecurringTransfer(ctx, act.UID, uid, rtr); err != nil {
+		t.Fatal("should create the recurring transfer", cross, err)
+	}
+	got, _, err := client.RecurringTransfer(ctx, act.UID, uid)
+	if err != nil || got.UID == "" || got.RecurrenceRule.Frequency != "MONTHLY" || got.Amount.MinorUnits != 500 {
+		t.Error("should return the recurring transfer", cross, got, err)
+	}
+	if _, err := client.DeleteRecurringTransfer(ctx, act.UID, uid); err != nil {
+		t.Error("should delete the recurring transfer", cross, err)
+	}
+	if _, _, err := client.RecurringTransfer(ctx, act.UID, uid); !starling.IsNotFound(err) {
+		t.Error("should return not found once the recurring transfer is deleted", cross, err)
+	}
+
+	if _, _, err := client.TransferToSavingsGoal(ctx, act.UID, uid, starling.Amount{Currency: "GBP", MinorUnits: 400}); err != nil {
+		t.Fatal("should transfer into the savings goal", cross, err)
+	}
+	if _, err := client.DeleteSavingsGoal(ctx, act.UID, uid); err != nil {
+		t.Fatal("should delete the savings goal", cross, err)
+	}
+	if got := srv.Balance(act.UID); got != 1000 {
+		t.Error("should return the money saved to the account", cross, got)
+	}
+	if _, _, err := client.SavingsGoal(ctx, act.UID, uid); !starling.IsNotFound(err) {
+		t.Error("should return not found for a deleted savings goal", cross, err)
+	}
+}
+
+func TestLocalPayment(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	from := srv.AddAccount(Account{Name: "Alice", Balance: 5000})
+	to := srv.AddAccount(Account{Name: "Bob"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	_, err := client.MakeLocalPayment(ctx, starling.LocalPayment{
+		Payment:               starling.PaymentAmount{Currency: "GBP", Amount: 12.34},
+		DestinationAccountUID: to.UID,
+		Reference:             "lunch",
+	})
+	if err != nil {
+		t.Fatal("should make the payment", cross, err)
+	}
+
+	if got := srv.Balance(from.UID); got != 3766 {
+		t.Error("should debit the paying account", cross, got)
+	}
+	if got := srv.Balance(to.UID); got != 1234 {
+		t.Error("should credit the receiving account", cross, got)
+	}
+
+	items := srv.FeedItems(to.UID, to.DefaultCategory)
+	if len(items) != 1 || items[0].CounterPartyName != "Alice" || items[0].Reference != "lunch" {
+		t.Error("should record the payment in the receiving feed", cross, items)
+	}
+
+	_, err = client.MakeLocalPayment(ctx, starling.LocalPayment{
+		Payment:               starling.PaymentAmount{Currency: "GBP", Amount: 100},
+		DestinationAccountUID: to.UID,
+	})
+	if !starling.IsInsufficientFunds(err) {
+		t.Error("should refuse a payment larger than the balance", cross, err)
+	}
+}
+
+func TestCardsAndMandates(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	card := srv.AddCard(starling.Card{Enabled: true})
+	mandate := srv.AddDirectDebitMandate(starling.DirectDebitMandate{OriginatorName: "Energy Co"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	if _, err := client.EnableCardOption(ctx, card.CardUID, "online", true); err != nil {
+		t.Fatal("should update the card control", cross, err)
+	}
+	if c, _ := srv.Card(card.CardUID); !c.OnlineEnabled {
+		t.Error("should enable online payments", cross)
+	}
+
+	cards, _, err := client.Cards(ctx)
+	if err != nil || len(cards) != 1 || cards[0].CardUID != card.CardUID || !cards[0].OnlineEnabled {
+		t.Error("should list the seeded card", cross, cards, err)
+	}
+
+	ms, _, err := client.DirectDebitMandates(ctx)
+	if err != nil || len(ms) != 1 || ms[0].UID != mandate.UID {
+		t.Error("should list the seeded mandate", cross, ms, err)
+	}
+
+	m, _, err := client.DirectDebitMandate(ctx, mandate.UID)
+	if err != nil || m.UID != mandate.UID || m.OriginatorName != "Energy Co" {
+		t.Error("should return the seeded mandate", cross, m, err)
+	}
+
+	if _, err := client.DeleteDirectDebitMandate(ctx, mandate.UID); err != nil {
+		t.Error("should delete the mandate", cross, err)
+	}
+	if len(srv.DirectDebitMandates()) != 0 {
+		t.Error("should remove the mandate", cross)
+	}
+	if _, _, err := client.DirectDebitMandate(ctx, mandate.UID); !starling.IsNotFound(err) {
+		t.Error("should return not found for a deleted mandate", cross, err)
+	}
+}
+
+func TestScheduledPayments(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	to := srv.AddAccount(Account{})
+	client := srv.Client()
+	ctx := context.Background()
+
+	uid, _, err := client.CreateScheduledPayment(ctx, starling.ScheduledPayment{
+		LocalPayment: starling.LocalPayment{
+			Payment:               starling.PaymentAmount{Currency: "GBP", Amount: 25},
+			DestinationAccountUID: to.UID,
+			Reference:             "rent",
+		},
+		Schedule: starling.RecurrenceRule{StartDate: "2020-07-01", Frequency: "MONTHLY"},
+	})
+	if err != nil || uid == "" {
+		t.Fatal("should create the scheduled payment", cross, uid, err)
+	}
+
+	pos, _, err := client.ScheduledPayments(ctx)
+	if err != nil || len(pos) != 1 {
+		t.Fatal("should list the scheduled payment", cross, pos, err)
+	}
+	if po := pos[0]; po.UID != uid || po.Amount != 25 || po.Reference != "rent" || po.NextDate != "2020-07-01" {
+		t.Error("should return the details of the scheduled payment", cross, po)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddAccount(Account{})
+	ctx := context.Background()
+
+	t.Run("forbidden", func(tc *testing.T) {
+		srv.InjectFault(Fault{Path: "/api/v2/accounts", Status: http.StatusForbidden, Times: 1})
+
+		_, _, err := srv.Client().Accounts(ctx)
+		var authErr starling.AuthError
+		if !errors.As(err, &authErr) {
+			tc.Error("should return an AuthError", cross, err)
+		}
+	})
+
+	t.Run("rate limited then retried", func(tc *testing.T) {
+		srv.InjectFault(Fault{Status: http.StatusTooManyRequests, Times: 2})
+
+		client := srv.ClientWithOptions(starling.ClientOptions{
+			Retry: &starling.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		})
+		acts, _, err := client.Accounts(ctx)
+		if err != nil || len(acts) != 1 {
+			tc.Error("should succeed once the fault is exhausted", cross, err)
+		}
+	})
+
+	t.Run("fail next", func(tc *testing.T) {
+		srv.FailNext(http.StatusServiceUnavailable, 1)
+
+		_, _, err := srv.Client().Accounts(ctx)
+		if e, ok := err.(starling.Error); !ok || !e.Temporary() {
+			tc.Error("should fail the next request", cross, err)
+		}
+		if _, _, err := srv.Client().Accounts(ctx); err != nil {
+			tc.Error("should succeed once the requests have failed", cross, err)
+		}
+	})
+
+	t.Run("server error until cleared", func(tc *testing.T) {
+		srv.InjectFault(Fault{Method: http.MethodGet, Status: http.StatusInternalServerError})
+
+		for i := 0; i < 2; i++ {
+			_, _, err := srv.Client().Accounts(ctx)
+			if e, ok := err.(starling.Error); !ok || !e.Temporary() {
+				tc.Error("should return a temporary error", cross, err)
+			}
+		}
+
+		srv.ClearFaults()
+		if _, _, err := srv.Client().Accounts(ctx); err != nil {
+			tc.Error("should succeed once faults are cleared", cross, err)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package starlingtest
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/astravexton/starling"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// WebhookSigner signs webhook payloads the way Starling does, so that handlers can be tested with
+// validly signed requests:
+//
+//	signer, _ := starlingtest.NewWebhookSigner()
+//	h, _ := starling.NewWebhookHandler(signer.PublicKey())
+//
+//	payload := starlingtest.CardPurchase(starlingtest.WebhookItem{AccountUID: act.UID, Amount: 450})
+//	req, _ := signer.Request("/webhooks", payload)
+//	h.ServeHTTP(httptest.NewRecorder(), req)
+type WebhookSigner struct {
+	key crypto.Signer
+}
+
+// NewWebhookSigner returns a WebhookSigner with a newly generated RSA key.
+func NewWebhookSigner() (*WebhookSigner, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate webhook key")
+	}
+	return &WebhookSigner{key: key}, nil
+}
+
+// NewWebhookSignerFromKey returns a WebhookSigner that signs with an RSA or ECDSA private key.
+func NewWebhookSignerFromKey(key crypto.Signer) *WebhookSigner {
+	return &WebhookSigner{key: key}
+}
+
+// PublicKey returns the base64-encoded DER public key, in the form shown in the Starling
+// developer portal.
+func (s *WebhookSigner) PublicKey() string {
+	der, err := x509.MarshalPKIXPublicKey(s.key.Public())
+	if err != nil {
+		panic("starlingtest: unable to marshal webhook public key: " + err.Error())
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+// Sign returns the value of the X-Hook-Signature header for body.
+func (s *WebhookSigner) Sign(body []byte) (string, error) {
+	digest := sha512.Sum512(body)
+	sig, err := s.key.Sign(rand.Reader, digest[:], crypto.SHA512)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to sign webhook")
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Request returns a signed webhook delivery to url. The payload is sent as is if it is a []byte
+// and encoded as JSON otherwise.
+func (s *WebhookSigner) Request(url string, payload interface{}) (*http.Request, error) {
+	body, ok := payload.([]byte)
+	if !ok {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return nil, errors.Wrap(err, "unable to encode webhook")
+		}
+	}
+
+	sig, err := s.Sign(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hook-Signature", sig)
+	return req, nil
+}
+
+// WebhookItem describes the transaction a webhook payload is built for. Fields left empty are
+// given generated values.
+type WebhookItem struct {
+	AccountHolderUID string
+	AccountUID       string
+	CategoryUID      string
+	CounterParty     string    // Name of the merchant, sender or originator
+	Amount           int64     // Amount in minor units
+	Currency         string    // Defaults to GBP
+	Reference        string    // Defaults to the counterparty
+	Time             time.Time // Defaults to now
+}
+
+// payload builds a feed item webhook for the item. Callers set the fields specific to the kind of
+// transaction.
+func (w WebhookItem) payload(direction, source, status, counterPartyType string) starling.WebHookPayload {
+	if w.AccountHolderUID == "" {
+		w.AccountHolderUID = uuid.New().String()
+	}
+	if w.AccountUID == "" {
+		w.AccountUID = uuid.New().String()
+	}
+	if w.CategoryUID == "" {
+		w.CategoryUID = uuid.New().String()
+	}
+	if w.Currency == "" {
+		w.Currency = "GBP"
+	}
+	if w.Reference == "" {
+		w.Reference = w.CounterParty
+	}
+	if w.Time.IsZero() {
+		w.Time = time.Now().UTC()
+	}
+
+	amount := starling.Amount{Currency: w.Currency, MinorUnits: w.Amount}
+	return starling.WebHookPayload{
+		WebhookEventUID:  uuid.New().String(),
+		EventTimestamp:   w.Time,
+		AccountHolderUID: w.AccountHolderUID,
+		Content: starling.WebHookFeedItem{
+			FeedItem: starling.FeedItem{
+				FeedItemUID:      uuid.New().String(),
+				CategoryUID:      w.CategoryUID,
+				AccountUID:       w.AccountUID,
+				Amount:           amount,
+				SourceAmount:     amount,
+				Direction:        direction,
+				UpdatedAt:        w.Time,
+				TransactionTime:  w.Time,
+				Source:           source,
+				Status:           status,
+				CounterPartyType: counterPartyType,
+				CounterPartyUID:  uuid.New().String(),
+				CounterPartyName: w.CounterParty,
+				Reference:        w.Reference,
+				Country:          "GB",
+			},
+			AccountUID: w.AccountUID,
+		},
+	}
+}
+
+// CardPurchase returns a webhook for a pending card payment to a merchant.
+func CardPurchase(w WebhookItem) starling.WebHookPayload {
+	if w.CounterParty == "" {
+		w.CounterParty = "Pret A Manger"
+	}
+	p := w.payload("OUT", "MASTER_CARD", "PENDING", "MERCHANT")
+	p.Content.SourceSubType = "CONTACTLESS"
+	p.Content.CounterPartySubEntityUID = uuid.New().String()
+	p.Content.CounterPartySubEntityName = w.CounterParty
+	p.Content.SpendingCategory = starling.SpendingCategoryEatingOut
+	p.Content.MasterCardFeedDetails = starling.MasterCardFeedItem{
+		MerchantIdentifier: "512345678",
+		MCC:                5812,
+		PosTimestamp:       p.EventTimestamp,
+		CardLast4:          "1234",
+	}
+	return p
+}
+
+// FasterPaymentIn returns a webhook for a settled Faster Payment received from another bank.
+func FasterPaymentIn(w WebhookItem) starling.WebHookPayload {
+	if w.CounterParty == "" {
+		w.CounterParty = "Jane Smith"
+	}
+	p := w.payload("IN", "FASTER_PAYMENTS_IN", "SETTLED", "SENDER")
+	p.Content.SettlementTime = p.Content.TransactionTime
+	p.Content.SpendingCategory = starling.SpendingCategoryIncome
+	p.Content.CounterPartySubEntityIdentifier = "608371"
+	p.Content.CounterPartSubEntitySubIdentifier = "12345678"
+	return p
+}
+
+// DirectDebit returns a webhook for a settled direct debit collected by an originator.
+func DirectDebit(w WebhookItem) starling.WebHookPayload {
+	if w.CounterParty == "" {
+		w.CounterParty = "British Gas"
+	}
+	p := w.payload("OUT", "DIRECT_DEBIT", "SETTLED", "PAYEE")
+	p.Content.SettlementTime = p.Content.TransactionTime
+	p.Content.SpendingCategory = starling.SpendingCategoryBillsAndServices
+	return p
+}
+
+// Reversal returns a webhook reversing the transaction in p. The feed item keeps its UID, as
+// it does when Starling reverses a transaction, but the event is new.
+func Reversal(p starling.WebHookPayload) starling.WebHookPayload {
+	now := time.Now().UTC()
+	if now.Before(p.EventTimestamp) {
+		now = p.EventTimestamp
+	}
+
+	p.WebhookEventUID = uuid.New().String()
+	p.EventTimestamp = now
+	p.Content.Status = "REVERSED"
+	p.Content.UpdatedAt = now
+	return p
+}
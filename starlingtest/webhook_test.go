@@ -0,0 +1,87 @@
+package starlingtest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/astravexton/starling"
+)
+
+func TestWebhookSigner(t *testing.T) {
+	signer, err := NewWebhookSigner()
+	if err != nil {
+		t.Fatal("should create a signer", cross, err)
+	}
+	h, err := starling.NewWebhookHandler(signer.PublicKey())
+	if err != nil {
+		t.Fatal("should accept the signer's public key", cross, err)
+	}
+
+	var got []*starling.WebHookFeedItem
+	h.OnFeedItem(func(ctx context.Context, e *starling.WebHookEvent, item *starling.WebHookFeedItem) error {
+		got = append(got, item)
+		return nil
+	})
+
+	purchase := CardPurchase(WebhookItem{AccountUID: "acc-1", Amount: 450})
+	payloads := []starling.WebHookPayload{
+		purchase,
+		FasterPaymentIn(WebhookItem{AccountUID: "acc-1", CounterParty: "Acme Ltd", Amount: 250000}),
+		DirectDebit(WebhookItem{AccountUID: "acc-1", Amount: 6500}),
+		Reversal(purchase),
+	}
+	for _, p := range payloads {
+		req, err := signer.Request("/webhooks", p)
+		if err != nil {
+			t.Fatal("should sign the payload", cross, err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Error("should deliver a validly signed webhook", cross, w.Code)
+		}
+	}
+
+	if len(got) != len(payloads) {
+		t.Fatal("should dispatch every payload", cross, len(got))
+	}
+	if got[0].Source != "MASTER_CARD" || got[0].MasterCardFeedDetails.MCC == 0 || got[0].Amount.MinorUnits != 450 || got[0].AccountUID != "acc-1" {
+		t.Error("should build a card purchase", cross, got[0])
+	}
+	if got[1].Direction != "IN" || got[1].Source != "FASTER_PAYMENTS_IN" || got[1].CounterPartyName != "Acme Ltd" {
+		t.Error("should build a faster payment", cross, got[1])
+	}
+	if got[2].Source != "DIRECT_DEBIT" || got[2].Direction != "OUT" {
+		t.Error("should build a direct debit", cross, got[2])
+	}
+	if got[3].Status != "REVERSED" || got[3].FeedItemUID != got[0].FeedItemUID {
+		t.Error("should reverse the same feed item", cross, got[3])
+	}
+}
+
+func TestWebhookSignerFromKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("should generate a key", cross, err)
+	}
+	signer := NewWebhookSignerFromKey(key)
+
+	v := starling.NewVerifier()
+	if err := v.AddKey("test", []byte(signer.PublicKey())); err != nil {
+		t.Fatal("should accept the signer's public key", cross, err)
+	}
+
+	body := []byte(`{"webhookEventUid":"e1"}`)
+	sig, err := signer.Sign(body)
+	if err != nil {
+		t.Fatal("should sign the body", cross, err)
+	}
+	if _, err := v.Verify(body, sig); err != nil {
+		t.Error("should produce a valid signature", cross, err)
+	}
+}
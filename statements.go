@@ -0,0 +1,84 @@
+package starling
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// StatementFormat is the format in which a statement is downloaded
+type StatementFormat string
+
+// Statement formats
+const (
+	StatementCSV StatementFormat = "text/csv"
+	StatementPDF StatementFormat = "application/pdf"
+)
+
+// StatementPeriod is a month for which a statement is available
+type StatementPeriod struct {
+	Period  string    `json:"period"`  // Month of the statement, formatted as yyyy-mm
+	Partial bool      `json:"partial"` // True if the month has not yet ended
+	EndsAt  time.Time `json:"endsAt"`
+}
+
+// statementPeriods is a list of the statement periods available for an account
+type statementPeriods struct {
+	Periods []StatementPeriod `json:"periods"`
+}
+
+// StatementPeriods returns the months for which statements are available for an account. It
+// also returns the http response in case this is required for further processing. An error will
+// be returned if unable to retrieve the periods from the API.
+func (c *Client) StatementPeriods(ctx context.Context, act string) ([]StatementPeriod, *http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/accounts/"+act+"/statement/available-periods", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sp statementPeriods
+	resp, err := c.Do(ctx, req, &sp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return sp.Periods, resp, nil
+}
+
+// DownloadStatement writes the statement for a month, formatted as yyyy-mm, to w in the given
+// format. The statement is streamed rather than held in memory. It returns the http response
+// in case this is required for further processing. An error is returned on failure, in which
+// case part of the statement may already have been written to w.
+func (c *Client) DownloadStatement(ctx context.Context, act, yearMonth string, format StatementFormat, w io.Writer) (*http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/accounts/"+act+"/statement/download", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("yearMonth", yearMonth)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Accept", string(format))
+
+	return c.stream(ctx, req, w)
+}
+
+// DownloadStatementForRange writes a statement covering the dates in dr to w in the given format.
+// Only the dates of From and To are used. The statement is streamed rather than held in memory.
+// It returns the http response in case this is required for further processing. An error is
+// returned on failure, in which case part of the statement may already have been written to w.
+func (c *Client) DownloadStatementForRange(ctx context.Context, act string, dr DateRange, format StatementFormat, w io.Writer) (*http.Response, error) {
+	req, err := c.NewRequest("GET", "/api/v2/accounts/"+act+"/statement/downloadForDateRange", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("start", dr.From.Format("2006-01-02"))
+	q.Add("end", dr.To.Format("2006-01-02"))
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Accept", string(format))
+
+	return c.stream(ctx, req, w)
+}
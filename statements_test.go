@@ -0,0 +1,141 @@
+package starling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatementPeriods(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/accounts/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/statement/available-periods", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"periods": [
+				{"period": "2020-05", "partial": false},
+				{"period": "2020-06", "partial": true, "endsAt": "2020-06-30T23:59:59.999Z"}
+			]
+		}`)
+	})
+
+	got, _, err := client.StatementPeriods(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6")
+	checkNoError(t, err)
+
+	want := []StatementPeriod{
+		{Period: "2020-05"},
+		{Period: "2020-06", Partial: true, EndsAt: time.Date(2020, 6, 30, 23, 59, 59, 999000000, time.UTC)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("should return the statement periods", cross, got)
+	}
+}
+
+func TestDownloadStatement(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	csv := "Date,Counter Party,Reference,Type,Amount (GBP),Balance (GBP)\n01/06/2020,ACME,SALARY,FASTER PAYMENT,2500.00,2500.00\n"
+
+	mux.HandleFunc("/api/v2/accounts/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/statement/download", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		if got := r.Header.Get("Accept"); got != "text/csv" {
+			t.Error("should request the statement as CSV", cross, got)
+		}
+		if got := r.URL.Query().Get("yearMonth"); got != "2020-06" {
+			t.Error("should request the given month", cross, got)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		fmt.Fprint(w, csv)
+	})
+
+	var buf bytes.Buffer
+	resp, err := client.DownloadStatement(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", "2020-06", StatementCSV, &buf)
+	checkNoError(t, err)
+	checkStatus(t, resp, http.StatusOK)
+
+	if buf.String() != csv {
+		t.Error("should write the statement to the writer", cross, buf.String())
+	}
+}
+
+func TestDownloadStatementForRange(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/accounts/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/statement/downloadForDateRange", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		if got := r.Header.Get("Accept"); got != "application/pdf" {
+			t.Error("should request the statement as a PDF", cross, got)
+		}
+		q := r.URL.Query()
+		if q.Get("start") != "2020-04-06" || q.Get("end") != "2021-04-05" {
+			t.Error("should request the given dates", cross, r.URL.RawQuery)
+		}
+		fmt.Fprint(w, "%PDF-1.4")
+	})
+
+	dr := DateRange{
+		From: time.Date(2020, 4, 6, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2021, 4, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	_, err := client.DownloadStatementForRange(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", dr, StatementPDF, &buf)
+	checkNoError(t, err)
+
+	if buf.String() != "%PDF-1.4" {
+		t.Error("should write the statement to the writer", cross, buf.String())
+	}
+}
+
+func TestDownloadStatementError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/accounts/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/statement/download", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errors":[{"message":"INVALID_YEAR_MONTH"}],"success":false}`)
+	})
+
+	var buf bytes.Buffer
+	_, err := client.DownloadStatement(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", "June", StatementCSV, &buf)
+	checkHasError(t, err)
+
+	if !IsValidation(err) {
+		t.Error("should return a validation error", cross, err)
+	}
+	if buf.Len() != 0 {
+		t.Error("should not write the error response to the writer", cross, buf.String())
+	}
+}
+
+func TestDownloadStatementRetry(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.retry = testRetryPolicy()
+
+	calls := 0
+	mux.HandleFunc("/api/v2/accounts/24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6/statement/download", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, "unavailable")
+			return
+		}
+		fmt.Fprint(w, "statement")
+	})
+
+	var buf bytes.Buffer
+	_, err := client.DownloadStatement(context.Background(), "24492cc9-2a41-4d2f-8c1b-7d5a27b1d4f6", "2020-06", StatementCSV, &buf)
+	checkNoError(t, err)
+
+	if calls != 2 || buf.String() != "statement" {
+		t.Error("should retry and write only the successful response", cross, calls, buf.String())
+	}
+}
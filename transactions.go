@@ -0,0 +1,179 @@
+package starling
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultTransactionConcurrency is the number of feeds fetched at once by Transactions when
+// TransactionOptions.Concurrency is not set.
+const defaultTransactionConcurrency = 4
+
+// TransactionOptions controls which transactions are returned by Transactions. The zero value
+// returns every transaction in every category of every account.
+type TransactionOptions struct {
+	Since        time.Time  // Only include items changed since this time; ignored if Between is set
+	Between      *DateRange // Only include items with a transaction time in this range
+	Direction    string     // Only include items in this direction, IN or OUT; all if empty
+	Status       []string   // Only include items with one of these statuses; all if empty
+	MinAmount    int64      // Only include items of at least this amount in minor units
+	MaxAmount    int64      // Only include items of at most this amount in minor units; no limit if zero
+	CounterParty string     // Only include items with this counterparty UID or with a name containing it, ignoring case
+	Concurrency  int        // Number of feeds fetched at once; defaults to 4
+}
+
+// match reports whether an item passes the filters in the options.
+func (o *TransactionOptions) match(it FeedItem) bool {
+	if o.Direction != "" && it.Direction != o.Direction {
+		return false
+	}
+	if len(o.Status) > 0 {
+		found := false
+		for _, s := range o.Status {
+			if it.Status == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if it.Amount.MinorUnits < o.MinAmount || (o.MaxAmount != 0 && it.Amount.MinorUnits > o.MaxAmount) {
+		return false
+	}
+	if o.CounterParty != "" && it.CounterPartyUID != o.CounterParty &&
+		!strings.Contains(strings.ToLower(it.CounterPartyName), strings.ToLower(o.CounterParty)) {
+		return false
+	}
+	return true
+}
+
+// feedCategory identifies a category of an account whose feed is fetched by Transactions.
+type feedCategory struct {
+	act, cat string
+}
+
+// Transactions returns the feed items in every category of every account, including savings
+// goals, merged in transaction time order and filtered as described by opts. Feeds are fetched
+// concurrently. It also returns the http response of the accounts request, or of the request that
+// failed, in case this is required for further processing. An error will be returned if unable to
+// retrieve any of the accounts, savings goals or feeds from the API.
+func (c *Client) Transactions(ctx context.Context, opts *TransactionOptions) ([]FeedItem, *http.Response, error) {
+	if opts == nil {
+		opts = &TransactionOptions{}
+	}
+
+	acts, resp, err := c.Accounts(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var cats []feedCategory
+	for _, act := range acts {
+		cats = append(cats, feedCategory{act: act.UID, cat: act.DefaultCategory})
+
+		goals, gResp, err := c.SavingsGoals(ctx, act.UID)
+		if err != nil {
+			return nil, gResp, err
+		}
+		for _, g := range goals {
+			cats = append(cats, feedCategory{act: act.UID, cat: g.UID})
+		}
+	}
+
+	feeds, fResp, err := c.categoryFeeds(ctx, cats, opts)
+	if err != nil {
+		return nil, fResp, err
+	}
+
+	var items []FeedItem
+	for i, f := range feeds {
+		for _, it := range f {
+			if !opts.match(it) {
+				continue
+			}
+			if it.AccountUID == "" {
+				it.AccountUID = cats[i].act
+			}
+			if it.CategoryUID == "" {
+				it.CategoryUID = cats[i].cat
+			}
+			items = append(items, it)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].TransactionTime.Before(items[j].TransactionTime) })
+	return items, resp, nil
+}
+
+// categoryFeeds fetches the feed of each category, running at most opts.Concurrency requests at
+// once. The first error cancels the requests still to run.
+func (c *Client) categoryFeeds(ctx context.Context, cats []feedCategory, opts *TransactionOptions) ([][]FeedItem, *http.Response, error) {
+	n := opts.Concurrency
+	if n <= 0 {
+		n = defaultTransactionConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		feeds   = make([][]FeedItem, len(cats))
+		sem     = make(chan struct{}, n)
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errResp *http.Response
+		err     error
+	)
+	fail := func(resp *http.Response, e error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil {
+			errResp, err = resp, e
+			cancel()
+		}
+	}
+
+	for i, fc := range cats {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(nil, ctx.Err())
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, fc feedCategory) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var (
+				items []FeedItem
+				resp  *http.Response
+				e     error
+			)
+			if opts.Between != nil {
+				items, resp, e = c.FeedBetween(ctx, fc.act, fc.cat, *opts.Between)
+			} else {
+				items, resp, e = c.Feed(ctx, fc.act, fc.cat, opts.Since)
+			}
+			if e != nil {
+				fail(resp, e)
+				return
+			}
+			feeds[i] = items
+		}(i, fc)
+	}
+	wg.Wait()
+
+	if err != nil {
+		return nil, errResp, err
+	}
+	return feeds, nil, nil
+}
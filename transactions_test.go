@@ -0,0 +1,160 @@
+package starling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTransactions(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		checkMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"accounts":[
+			{"accountUid":"acc-1","defaultCategory":"cat-1","currency":"GBP"},
+			{"accountUid":"acc-2","defaultCategory":"cat-2","currency":"GBP"}
+		]}`)
+	})
+	mux.HandleFunc("/api/v2/account/acc-1/savings-goals", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"savingsGoalList":[{"uid":"goal-1","name":"Holiday"}]}`)
+	})
+	mux.HandleFunc("/api/v2/account/acc-2/savings-goals", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"savingsGoalList":[]}`)
+	})
+
+	var (
+		mu      sync.Mutex
+		fetched = map[string]bool{}
+	)
+	feeds := map[string]string{
+		"/api/v2/feed/account/acc-1/category/cat-1": `{"feedItems":[
+			{"feedItemUid":"item-1","direction":"OUT","status":"SETTLED","amount":{"currency":"GBP","minorUnits":450},"counterPartyName":"Pret A Manger","transactionTime":"2021-03-02T12:00:00Z"},
+			{"feedItemUid":"item-2","direction":"IN","status":"SETTLED","amount":{"currency":"GBP","minorUnits":250000},"counterPartyName":"Acme Ltd","transactionTime":"2021-03-01T09:00:00Z"}
+		]}`,
+		"/api/v2/feed/account/acc-1/category/goal-1": `{"feedItems":[
+			{"feedItemUid":"item-3","direction":"IN","status":"SETTLED","amount":{"currency":"GBP","minorUnits":5000},"counterPartyName":"Holiday","transactionTime":"2021-03-03T08:00:00Z"}
+		]}`,
+		"/api/v2/feed/account/acc-2/category/cat-2": `{"feedItems":[
+			{"feedItemUid":"item-4","direction":"OUT","status":"PENDING","amount":{"currency":"GBP","minorUnits":1299},"counterPartyName":"PRET A MANGER","transactionTime":"2021-03-01T18:30:00Z"}
+		]}`,
+	}
+	for path, body := range feeds {
+		path, body := path, body
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			checkMethod(t, r, http.MethodGet)
+			mu.Lock()
+			fetched[path] = true
+			mu.Unlock()
+			fmt.Fprint(w, body)
+		})
+	}
+
+	got, _, err := client.Transactions(context.Background(), nil)
+	checkNoError(t, err)
+
+	if len(fetched) != len(feeds) {
+		t.Error("should fetch the feed of every category", cross, fetched)
+	}
+	want := []string{"item-2", "item-4", "item-1", "item-3"}
+	if len(got) != len(want) {
+		t.Fatal("should return every transaction", cross, len(got))
+	}
+	for i, uid := range want {
+		if got[i].FeedItemUID != uid {
+			t.Error("should order transactions by time", cross, i, got[i].FeedItemUID)
+		}
+	}
+	if got[3].AccountUID != "acc-1" || got[3].CategoryUID != "goal-1" {
+		t.Error("should record the account and category of each transaction", cross, got[3].AccountUID, got[3].CategoryUID)
+	}
+
+	filters := []struct {
+		name string
+		opts TransactionOptions
+		want []string
+	}{
+		{name: "direction", opts: TransactionOptions{Direction: "OUT"}, want: []string{"item-4", "item-1"}},
+		{name: "status", opts: TransactionOptions{Status: []string{"PENDING"}}, want: []string{"item-4"}},
+		{name: "amount range", opts: TransactionOptions{MinAmount: 1000, MaxAmount: 10000}, want: []string{"item-4", "item-3"}},
+		{name: "counterparty", opts: TransactionOptions{CounterParty: "pret", Concurrency: 1}, want: []string{"item-4", "item-1"}},
+	}
+	for _, tc := range filters {
+		opts := tc.opts
+		got, _, err := client.Transactions(context.Background(), &opts)
+		checkNoError(t, err)
+
+		if len(got) != len(tc.want) {
+			t.Error("should filter by", tc.name, cross, len(got))
+			continue
+		}
+		for i, uid := range tc.want {
+			if got[i].FeedItemUID != uid {
+				t.Error("should filter by", tc.name, cross, i, got[i].FeedItemUID)
+			}
+		}
+	}
+}
+
+func TestTransactionsBetween(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"accounts":[{"accountUid":"acc-1","defaultCategory":"cat-1","currency":"GBP"}]}`)
+	})
+	mux.HandleFunc("/api/v2/account/acc-1/savings-goals", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"savingsGoalList":[]}`)
+	})
+	mux.HandleFunc("/api/v2/feed/account/acc-1/category/cat-1/transactions-between", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("minTransactionTimestamp") != "2021-03-01T00:00:00Z" {
+			t.Error("should request the range", cross, r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"feedItems":[{"feedItemUid":"item-1","transactionTime":"2021-03-02T12:00:00Z"}]}`)
+	})
+
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	got, _, err := client.Transactions(context.Background(), &TransactionOptions{
+		Between: &DateRange{From: from, To: from.AddDate(0, 0, 7)},
+	})
+	checkNoError(t, err)
+
+	if len(got) != 1 || got[0].FeedItemUID != "item-1" {
+		t.Error("should return the transactions in the range", cross, got)
+	}
+}
+
+func TestTransactionsFeedError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"accounts":[
+			{"accountUid":"acc-1","defaultCategory":"cat-1","currency":"GBP"},
+			{"accountUid":"acc-2","defaultCategory":"cat-2","currency":"GBP"}
+		]}`)
+	})
+	mux.HandleFunc("/api/v2/account/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"savingsGoalList":[]}`)
+	})
+	mux.HandleFunc("/api/v2/feed/account/acc-1/category/cat-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"feedItems":[]}`)
+	})
+	mux.HandleFunc("/api/v2/feed/account/acc-2/category/cat-2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	got, resp, err := client.Transactions(context.Background(), nil)
+	checkHasError(t, err)
+
+	if got != nil || !IsNotFound(err) {
+		t.Error("should return the error from the failed feed", cross, err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Error("should return the response of the failed request", cross, resp)
+	}
+}
@@ -0,0 +1,238 @@
+package starling
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Reasons for a webhook signature failing verification. A *VerificationError can be matched
+// against them using errors.Is.
+var (
+	ErrMissingSignature   = errors.New("missing signature")
+	ErrMalformedSignature = errors.New("malformed signature")
+	ErrSignatureMismatch  = errors.New("signature does not match any key")
+	ErrKeyRetired         = errors.New("signature made with a retired key")
+	ErrNoVerificationKeys = errors.New("no active verification keys")
+)
+
+// VerificationError is returned when a webhook signature fails verification. Reason is one of the
+// sentinel errors above.
+type VerificationError struct {
+	Reason error  // Why verification failed
+	KeyID  string // Key the signature was made with, if it matched a retired key
+	Err    error  // Underlying error, if any
+}
+
+func (e *VerificationError) Error() string {
+	msg := "webhook verification failed: " + e.Reason.Error()
+	if e.KeyID != "" {
+		msg += " " + e.KeyID
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *VerificationError) Unwrap() error { return e.Err }
+
+// Is reports whether the error failed for the given reason.
+func (e *VerificationError) Is(target error) bool { return e.Reason == target }
+
+// verificationKey is a public key held by a Verifier.
+type verificationKey struct {
+	id      string
+	pub     crypto.PublicKey
+	retires time.Time // Zero unless the key is retiring
+}
+
+// active reports whether the key is accepted at t.
+func (k *verificationKey) active(t time.Time) bool {
+	return k.retires.IsZero() || t.Before(k.retires)
+}
+
+// verify reports whether sig is a signature of digest made with the key.
+func (k *verificationKey) verify(digest, sig []byte) bool {
+	switch pub := k.pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA512, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(pub, digest, sig)
+	}
+	return false
+}
+
+// Verifier checks webhook signatures against a set of public keys, accepting a signature made with
+// any active key. To rotate keys without downtime, add the new key, mark the old key as retiring
+// and remove it once Starling has switched over:
+//
+//	v := starling.NewVerifier()
+//	v.AddKey("2020", oldKey)
+//	v.AddKey("2021", newKey)
+//	v.Retire("2020", time.Now().Add(24*time.Hour))
+//
+// It is safe for concurrent use.
+type Verifier struct {
+	mu   sync.RWMutex
+	keys []*verificationKey
+}
+
+// NewVerifier returns a Verifier with no keys.
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+// AddKey adds an RSA or ECDSA public key, replacing any key with the same ID. The key may be PEM
+// encoded or base64-encoded DER, such as the webhook public key shown in the Starling developer
+// portal. An error is returned if the key cannot be parsed.
+func (v *Verifier) AddKey(id string, key []byte) error {
+	pub, err := ParseVerificationKey(key)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for i, k := range v.keys {
+		if k.id == id {
+			v.keys[i] = &verificationKey{id: id, pub: pub}
+			return nil
+		}
+	}
+	v.keys = append(v.keys, &verificationKey{id: id, pub: pub})
+	return nil
+}
+
+// Retire marks a key as retiring. Signatures made with it are accepted until at and rejected with
+// ErrKeyRetired afterwards. An error is returned if there is no key with the ID.
+func (v *Verifier) Retire(id string, at time.Time) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, k := range v.keys {
+		if k.id == id {
+			k.retires = at
+			return nil
+		}
+	}
+	return errors.Errorf("no verification key %q", id)
+}
+
+// RemoveKey removes a key. Removing a key that does not exist has no effect.
+func (v *Verifier) RemoveKey(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for i, k := range v.keys {
+		if k.id == id {
+			v.keys = append(v.keys[:i], v.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// Retiring reports whether a key has been marked as retiring. Check the key returned by Verify
+// to find deliveries still signed with a key that is about to be retired.
+func (v *Verifier) Retiring(id string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, k := range v.keys {
+		if k.id == id {
+			return !k.retires.IsZero()
+		}
+	}
+	return false
+}
+
+// Verify checks that sig is a base64-encoded SHA-512 signature of body made with one of the
+// active keys, returning the ID of the key that matched. A *VerificationError is returned if the
+// signature is not accepted.
+func (v *Verifier) Verify(body []byte, sig string) (string, error) {
+	if sig == "" {
+		return "", &VerificationError{Reason: ErrMissingSignature}
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return "", &VerificationError{Reason: ErrMalformedSignature, Err: err}
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	digest := sha512.Sum512(body)
+	now := time.Now()
+	var retired []*verificationKey
+	for _, k := range v.keys {
+		if !k.active(now) {
+			retired = append(retired, k)
+			continue
+		}
+		if k.verify(digest[:], raw) {
+			return k.id, nil
+		}
+	}
+
+	for _, k := range retired {
+		if k.verify(digest[:], raw) {
+			return "", &VerificationError{Reason: ErrKeyRetired, KeyID: k.id}
+		}
+	}
+	if len(retired) == len(v.keys) {
+		return "", &VerificationError{Reason: ErrNoVerificationKeys}
+	}
+	return "", &VerificationError{Reason: ErrSignatureMismatch}
+}
+
+// VerifyRequest verifies the X-Hook-Signature header of a webhook request against its body,
+// returning the ID of the key that matched. The body is restored so that it can be read again.
+func (v *Verifier) VerifyRequest(r *http.Request) (string, error) {
+	if r.Body == nil {
+		return "", fmt.Errorf("no body to validate")
+	}
+
+	buf, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewBuffer(buf))
+
+	return v.Verify(buf, r.Header.Get("X-Hook-Signature"))
+}
+
+// ParseVerificationKey parses an RSA or ECDSA public key in PKIX form. The key may be PEM encoded,
+// base64-encoded DER or raw DER.
+func ParseVerificationKey(data []byte) (crypto.PublicKey, error) {
+	der := data
+	if block, _ := pem.Decode(data); block != nil {
+		der = block.Bytes
+	} else if b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err == nil {
+		der = b
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse public key")
+	}
+	switch pub.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return pub, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", pub)
+}
@@ -0,0 +1,160 @@
+package starling
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sign returns the base64-encoded SHA-512 signature of body.
+func sign(t *testing.T, key crypto.Signer, body string) string {
+	digest := sha512.Sum512([]byte(body))
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA512)
+	if err != nil {
+		t.Fatal("should sign the body", cross, err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestVerifier(t *testing.T) {
+	rsaKey, rsaPub := webhookKey(t)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	checkNoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	checkNoError(t, err)
+	ecPub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	v := NewVerifier()
+	checkNoError(t, v.AddKey("old", []byte(rsaPub)))
+	checkNoError(t, v.AddKey("new", ecPub))
+
+	body := `{"webhookEventUid":"e1"}`
+	for _, tc := range []struct {
+		key crypto.Signer
+		id  string
+	}{
+		{rsaKey, "old"},
+		{ecKey, "new"},
+	} {
+		id, err := v.Verify([]byte(body), sign(t, tc.key, body))
+		checkNoError(t, err)
+		if id != tc.id {
+			t.Error("should return the key that matched", cross, id)
+		}
+	}
+
+	checkNoError(t, v.Retire("old", time.Now().Add(time.Hour)))
+	if id, err := v.Verify([]byte(body), sign(t, rsaKey, body)); err != nil || !v.Retiring(id) {
+		t.Error("should accept a retiring key until it retires", cross, id, err)
+	}
+
+	checkNoError(t, v.Retire("old", time.Now().Add(-time.Second)))
+	_, err = v.Verify([]byte(body), sign(t, rsaKey, body))
+	var ve *VerificationError
+	if !errors.Is(err, ErrKeyRetired) || !errors.As(err, &ve) || ve.KeyID != "old" {
+		t.Fatal("should reject a retired key", cross, err)
+	}
+	if got := err.Error(); got != "webhook verification failed: signature made with a retired key old" {
+		t.Error("should name the retired key in the message", cross, got)
+	}
+	if errors.Unwrap(err) != nil {
+		t.Error("should have no underlying error", cross, errors.Unwrap(err))
+	}
+
+	checkHasError(t, v.Retire("missing", time.Now()))
+}
+
+func TestVerifierErrors(t *testing.T) {
+	key, pub := webhookKey(t)
+	other, _ := webhookKey(t)
+	body := `{"webhookEventUid":"e1"}`
+
+	v := NewVerifier()
+	checkNoError(t, v.AddKey("current", []byte(pub)))
+
+	cases := []struct {
+		name   string
+		sig    string
+		reason error
+	}{
+		{"missing signature", "", ErrMissingSignature},
+		{"malformed signature", "[invalid]signature", ErrMalformedSignature},
+		{"wrong key", sign(t, other, body), ErrSignatureMismatch},
+	}
+	for _, tc := range cases {
+		_, err := v.Verify([]byte(body), tc.sig)
+		if !errors.Is(err, tc.reason) {
+			t.Error("should explain why verification failed:", tc.name, cross, err)
+		}
+		if err != nil && !strings.HasPrefix(err.Error(), "webhook verification failed: "+tc.reason.Error()) {
+			t.Error("should describe the reason in the message:", tc.name, cross, err)
+		}
+	}
+
+	_, err := v.Verify([]byte(body), "[invalid]signature")
+	var decodeErr base64.CorruptInputError
+	if !errors.As(errors.Unwrap(err), &decodeErr) {
+		t.Errorf("should unwrap to the decoding error %s %T", cross, errors.Unwrap(err))
+	}
+	if !strings.HasSuffix(err.Error(), ": "+decodeErr.Error()) {
+		t.Error("should include the underlying error in the message", cross, err)
+	}
+
+	v.RemoveKey("current")
+	if _, err := v.Verify([]byte(body), sign(t, key, body)); !errors.Is(err, ErrNoVerificationKeys) {
+		t.Error("should fail without keys", cross, err)
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	v := NewVerifier()
+	checkNoError(t, v.AddKey("starling", []byte(publicKey)))
+
+	body := []byte(`{"one":"Value","two":"Other"}`)
+	req, err := http.NewRequest("POST", "http://localhost/callback", bytes.NewBuffer(body))
+	checkNoError(t, err)
+	req.Header.Set("X-Hook-Signature", signature)
+
+	id, err := v.VerifyRequest(req)
+	checkNoError(t, err)
+	if id != "starling" {
+		t.Error("should return the key that matched", cross, id)
+	}
+
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(req.Body)
+	if !bytes.Equal(buf.Bytes(), body) {
+		t.Error("should restore the request body", cross, buf.String())
+	}
+}
+
+func TestParseVerificationKey(t *testing.T) {
+	_, pub := webhookKey(t)
+	der, _ := base64.StdEncoding.DecodeString(pub)
+
+	for name, data := range map[string][]byte{
+		"base64": []byte(pub + "\n"),
+		"der":    der,
+		"pem":    pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+	} {
+		if _, err := ParseVerificationKey(data); err != nil {
+			t.Error("should parse a key encoded as", name, cross, err)
+		}
+	}
+
+	_, err := ParseVerificationKey([]byte("[invalid]publicKey"))
+	checkHasError(t, err)
+}
@@ -1,14 +1,6 @@
 package starling
 
 import (
-	"bytes"
-	"crypto"
-	"crypto/rsa"
-	"crypto/sha512"
-	"crypto/x509"
-	"encoding/base64"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 	"time"
 )
@@ -40,52 +32,16 @@ type MasterCardFeedItem struct {
 // Validate takes an http request and a base64-encoded web-hook public key
 // and validates the request signature matches the signature provided in
 // the X-Hook-Signature. An error is returned if unable to parse the body
-// of the request.
+// of the request. To accept several keys or to avoid parsing the key on
+// every request, use a Verifier.
 func Validate(r *http.Request, publicKey string) (bool, error) {
-	if r.Body == nil {
-		return false, fmt.Errorf("no body to validate")
-	}
-
-	buf, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return false, err
-	}
-
-	key, err := publicKeyFrom64(publicKey)
-	if err != nil {
-		return false, err
-	}
-	reqSig, err := base64.StdEncoding.DecodeString(r.Header.Get("X-Hook-Signature"))
-	if err != nil {
+	v := NewVerifier()
+	if err := v.AddKey("", []byte(publicKey)); err != nil {
 		return false, err
 	}
 
-	body := ioutil.NopCloser(bytes.NewBuffer(buf))
-	r.Body = body
-
-	digest := sha512.Sum512(buf)
-	err = rsa.VerifyPKCS1v15(key, crypto.SHA512, digest[:], reqSig)
-	if err != nil {
+	if _, err := v.VerifyRequest(r); err != nil {
 		return false, err
 	}
-
 	return true, nil
 }
-
-// Convert the base64 encoded public key to *rsa.PublicKey
-func publicKeyFrom64(key string) (*rsa.PublicKey, error) {
-	b, err := base64.StdEncoding.DecodeString(key)
-	if err != nil {
-		return nil, err
-	}
-	pubInterface, err := x509.ParsePKIXPublicKey(b)
-	if err != nil {
-		return nil, err
-	}
-	pub, ok := pubInterface.(*rsa.PublicKey)
-	if !ok {
-		return nil, err
-	}
-
-	return pub, nil
-}
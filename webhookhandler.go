@@ -0,0 +1,420 @@
+package starling
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxWebhookBody is the largest webhook payload accepted by WebhookHandler.
+const maxWebhookBody = 1 << 20
+
+// defaultWebhookRetention is how long a WebhookHandler remembers events when Retention is not set.
+const defaultWebhookRetention = 72 * time.Hour
+
+// WebhookType identifies the kind of event a webhook describes
+type WebhookType string
+
+// Webhook event types
+const (
+	WebhookTypeFeedItem      WebhookType = "FEED_ITEM"
+	WebhookTypeStandingOrder WebhookType = "STANDING_ORDER"
+	WebhookTypePayment       WebhookType = "PAYMENT"
+	WebhookTypeCardStatus    WebhookType = "CARD_STATUS"
+	WebhookTypeSavingsGoal   WebhookType = "SAVINGS_GOAL"
+	WebhookTypePayee         WebhookType = "PAYEE"
+)
+
+// WebHookEvent is a webhook delivery with its content left undecoded
+type WebHookEvent struct {
+	WebhookEventUID  string          `json:"webhookEventUid"`
+	Type             WebhookType     `json:"webhookType"`
+	EventTimestamp   time.Time       `json:"eventTimestamp"`
+	AccountHolderUID string          `json:"accountHolderUid"`
+	Content          json.RawMessage `json:"content"`
+}
+
+// WebHookStandingOrder is the content of a webhook sent when a standing order is created, changed
+// or cancelled
+type WebHookStandingOrder struct {
+	StandingOrder
+	AccountUID string `json:"accountUid"`
+}
+
+// WebHookPayment is the content of a webhook sent when the status of a payment changes
+type WebHookPayment struct {
+	PaymentDetail
+	AccountUID      string `json:"accountUid"`
+	CategoryUID     string `json:"categoryUid"`
+	PaymentOrderUID string `json:"paymentOrderUid"`
+}
+
+// WebHookCardStatus is the content of a webhook sent when a card is enabled, disabled or its
+// controls change
+type WebHookCardStatus struct {
+	Card
+	AccountUID string `json:"accountUid"`
+}
+
+// WebHookSavingsGoal is the content of a webhook sent when a savings goal is created, changed or
+// deleted
+type WebHookSavingsGoal struct {
+	SavingsGoalUID  string `json:"savingsGoalUid"`
+	AccountUID      string `json:"accountUid"`
+	Name            string `json:"name"`
+	Target          Amount `json:"target"`
+	TotalSaved      Amount `json:"totalSaved"`
+	SavedPercentage int32  `json:"savedPercentage"`
+	State           string `json:"state"`
+}
+
+// WebHookPayee is the content of a webhook sent when a payee is created, changed or deleted
+type WebHookPayee struct {
+	Payee
+	AccountHolderUID string `json:"accountHolderUid"`
+}
+
+// contentKeys identifies the type of an event without a webhookType from a field only present in
+// the content of that type. The keys are checked in order.
+var contentKeys = []struct {
+	key string
+	typ WebhookType
+}{
+	{"feedItemUid", WebhookTypeFeedItem},
+	{"standingOrderRecurrence", WebhookTypeStandingOrder},
+	{"paymentStatusDetails", WebhookTypePayment},
+	{"cardUid", WebhookTypeCardStatus},
+	{"savingsGoalUid", WebhookTypeSavingsGoal},
+	{"payeeUid", WebhookTypePayee},
+}
+
+// contentType returns the type of an event, inferring it from the content if the payload did not
+// name it.
+func (e *WebHookEvent) contentType() WebhookType {
+	if e.Type != "" {
+		return e.Type
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(e.Content, &fields); err != nil {
+		return ""
+	}
+	for _, ck := range contentKeys {
+		if _, ok := fields[ck.key]; ok {
+			return ck.typ
+		}
+	}
+	return ""
+}
+
+// ErrMalformedWebhook matches, using errors.Is, the error returned by Dispatch when the content of
+// an event cannot be decoded. Handling such an event again will fail in the same way.
+var ErrMalformedWebhook = errors.New("malformed webhook payload")
+
+// malformedError is returned when a webhook payload cannot be decoded. Starling is told not to
+// retry such deliveries.
+type malformedError struct {
+	err error
+}
+
+func (e *malformedError) Error() string { return "malformed webhook payload: " + e.err.Error() }
+
+// Is reports whether target is ErrMalformedWebhook.
+func (e *malformedError) Is(target error) bool { return target == ErrMalformedWebhook }
+
+// WebhookHandler is an http.Handler that receives Starling webhooks. It verifies the signature of
+// each delivery, decodes the payload and calls the callback registered for its type:
+//
+//	h, err := starling.NewWebhookHandler(publicKey)
+//	if err != nil {
+//		return err
+//	}
+//	h.OnFeedItem(func(ctx context.Context, e *starling.WebHookEvent, item *starling.WebHookFeedItem) error {
+//		return save(ctx, item)
+//	})
+//	http.Handle("/webhooks/starling", h)
+//
+// Deliveries that fail verification or cannot be decoded are rejected with a 4xx status. Those
+// for which the callback returns an error are answered with a 500 status so that Starling sends
+// them again. Deliveries with no registered callback are acknowledged and discarded.
+//
+// Setting Seen protects against replayed and redelivered events. Each event is then handled at
+// most once within the retention window: an event is marked as seen only after its callback
+// succeeds, redeliveries of a marked event are acknowledged without calling the callback, and a
+// redelivery arriving while the first delivery is still being handled is answered with a 409
+// status so that Starling sends it again later. Events without a UID, or with an EventTimestamp
+// further than MaxSkew from the current time, are rejected.
+//
+// Setting Queue acknowledges deliveries as soon as they are stored in the queue, rather than once
+// the callback has run. The queue then calls Dispatch to run the callbacks, retrying those that
+// fail. With Seen also set, an event is marked as seen once it has been queued.
+//
+// Callbacks and the fields below must be set before the handler starts serving requests.
+type WebhookHandler struct {
+	// OnError, if set, is called with the reason a delivery was not acknowledged, or was handled
+	// but could not be marked as seen.
+	OnError func(r *http.Request, err error)
+
+	Seen      SeenStore     // Remembers handled events; replay protection is disabled if nil
+	Retention time.Duration // How long handled events are remembered; defaults to 72 hours
+	MaxSkew   time.Duration // How far EventTimestamp may be from now; defaults to, and is capped at, Retention
+
+	Queue WebhookQueue // Stores verified events for later dispatch; callbacks are run during the request if nil
+
+	verifier  *Verifier
+	callbacks map[WebhookType]func(context.Context, *WebHookEvent) error
+	other     func(context.Context, *WebHookEvent) error
+
+	mu       sync.Mutex
+	inFlight map[string]bool // Events being handled, keyed by WebhookEventUID
+}
+
+// WebhookQueue stores verified webhook events until they can be dispatched. An event must be
+// stored durably before Enqueue returns, as the delivery is then acknowledged to Starling.
+type WebhookQueue interface {
+	Enqueue(e *WebHookEvent) error
+}
+
+// NewWebhookHandler returns a WebhookHandler that verifies deliveries using the base64-encoded
+// webhook public key shown in the Starling developer portal. An error is returned if the key
+// cannot be parsed.
+func NewWebhookHandler(publicKey string) (*WebhookHandler, error) {
+	v := NewVerifier()
+	if err := v.AddKey("", []byte(publicKey)); err != nil {
+		return nil, errors.Wrap(err, "invalid webhook public key")
+	}
+	return NewWebhookHandlerWithVerifier(v), nil
+}
+
+// NewWebhookHandlerWithVerifier returns a WebhookHandler that verifies deliveries using v. Keys
+// can be added to and retired from v while the handler is serving requests.
+func NewWebhookHandlerWithVerifier(v *Verifier) *WebhookHandler {
+	return &WebhookHandler{
+		verifier:  v,
+		callbacks: make(map[WebhookType]func(context.Context, *WebHookEvent) error),
+		inFlight:  make(map[string]bool),
+	}
+}
+
+// on registers a callback that decodes the content of events of type t into v.
+func (h *WebhookHandler) on(t WebhookType, v func() interface{}, fn func(context.Context, *WebHookEvent, interface{}) error) {
+	h.callbacks[t] = func(ctx context.Context, e *WebHookEvent) error {
+		c := v()
+		if err := json.Unmarshal(e.Content, c); err != nil {
+			return &malformedError{err: err}
+		}
+		return fn(ctx, e, c)
+	}
+}
+
+// OnFeedItem registers the callback for feed item events, replacing any registered before.
+func (h *WebhookHandler) OnFeedItem(fn func(ctx context.Context, e *WebHookEvent, item *WebHookFeedItem) error) {
+	h.on(WebhookTypeFeedItem, func() interface{} { return &WebHookFeedItem{} }, func(ctx context.Context, e *WebHookEvent, c interface{}) error {
+		return fn(ctx, e, c.(*WebHookFeedItem))
+	})
+}
+
+// OnStandingOrder registers the callback for standing order events, replacing any registered
+// before.
+func (h *WebhookHandler) OnStandingOrder(fn func(ctx context.Context, e *WebHookEvent, so *WebHookStandingOrder) error) {
+	h.on(WebhookTypeStandingOrder, func() interface{} { return &WebHookStandingOrder{} }, func(ctx context.Context, e *WebHookEvent, c interface{}) error {
+		return fn(ctx, e, c.(*WebHookStandingOrder))
+	})
+}
+
+// OnPayment registers the callback for payment status events, replacing any registered before.
+func (h *WebhookHandler) OnPayment(fn func(ctx context.Context, e *WebHookEvent, p *WebHookPayment) error) {
+	h.on(WebhookTypePayment, func() interface{} { return &WebHookPayment{} }, func(ctx context.Context, e *WebHookEvent, c interface{}) error {
+		return fn(ctx, e, c.(*WebHookPayment))
+	})
+}
+
+// OnCardStatus registers the callback for card status events, replacing any registered before.
+func (h *WebhookHandler) OnCardStatus(fn func(ctx context.Context, e *WebHookEvent, cs *WebHookCardStatus) error) {
+	h.on(WebhookTypeCardStatus, func() interface{} { return &WebHookCardStatus{} }, func(ctx context.Context, e *WebHookEvent, c interface{}) error {
+		return fn(ctx, e, c.(*WebHookCardStatus))
+	})
+}
+
+// OnSavingsGoal registers the callback for savings goal events, replacing any registered before.
+func (h *WebhookHandler) OnSavingsGoal(fn func(ctx context.Context, e *WebHookEvent, sg *WebHookSavingsGoal) error) {
+	h.on(WebhookTypeSavingsGoal, func() interface{} { return &WebHookSavingsGoal{} }, func(ctx context.Context, e *WebHookEvent, c interface{}) error {
+		return fn(ctx, e, c.(*WebHookSavingsGoal))
+	})
+}
+
+// OnPayee registers the callback for payee events, replacing any registered before.
+func (h *WebhookHandler) OnPayee(fn func(ctx context.Context, e *WebHookEvent, p *WebHookPayee) error) {
+	h.on(WebhookTypePayee, func() interface{} { return &WebHookPayee{} }, func(ctx context.Context, e *WebHookEvent, c interface{}) error {
+		return fn(ctx, e, c.(*WebHookPayee))
+	})
+}
+
+// OnOther registers the callback for events with no callback of their own, including those of
+// types the package does not know. The content is left for the callback to decode.
+func (h *WebhookHandler) OnOther(fn func(ctx context.Context, e *WebHookEvent) error) {
+	h.other = fn
+}
+
+// ServeHTTP verifies, decodes and dispatches a webhook delivery.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, errors.Errorf("unexpected method %s", r.Method))
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, errors.Wrap(err, "unable to read webhook payload"))
+		return
+	}
+	if len(body) > maxWebhookBody {
+		h.fail(w, r, http.StatusRequestEntityTooLarge, errors.New("webhook payload too large"))
+		return
+	}
+
+	if _, err := h.verifier.Verify(body, r.Header.Get("X-Hook-Signature")); err != nil {
+		h.fail(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	var e WebHookEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		h.fail(w, r, http.StatusBadRequest, &malformedError{err: err})
+		return
+	}
+
+	if h.Seen != nil {
+		if err := h.checkReplay(&e); err != nil {
+			h.fail(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// Claim the event before checking whether it was seen, so that a delivery that has run its
+		// callback but not yet marked the event cannot be missed by the check.
+		if !h.begin(e.WebhookEventUID) {
+			h.fail(w, r, http.StatusConflict, errors.Errorf("webhook %s is already being handled", e.WebhookEventUID))
+			return
+		}
+		defer h.end(e.WebhookEventUID)
+
+		seen, err := h.Seen.Seen(e.WebhookEventUID)
+		if err != nil {
+			h.fail(w, r, http.StatusInternalServerError, errors.Wrap(err, "unable to check for duplicate webhook"))
+			return
+		}
+		if seen {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	if h.Queue != nil {
+		if err := h.Queue.Enqueue(&e); err != nil {
+			h.fail(w, r, http.StatusInternalServerError, errors.Wrap(err, "unable to queue webhook"))
+			return
+		}
+		h.mark(r, &e)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = h.Dispatch(r.Context(), &e)
+	switch {
+	case errors.Is(err, ErrMalformedWebhook):
+		h.fail(w, r, http.StatusBadRequest, err)
+	case err != nil:
+		h.fail(w, r, http.StatusInternalServerError, err)
+	default:
+		h.mark(r, &e)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// Dispatch calls the callback registered for the type of an event, or the OnOther callback if
+// there is none. Events with no callback are ignored. It is called by ServeHTTP, or by the Queue
+// if one is set. An error matching ErrMalformedWebhook is returned if the content of the event
+// cannot be decoded; otherwise the error from the callback is returned.
+func (h *WebhookHandler) Dispatch(ctx context.Context, e *WebHookEvent) error {
+	fn, ok := h.callbacks[e.contentType()]
+	if !ok {
+		fn = h.other
+	}
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, e)
+}
+
+// retention returns how long handled events are remembered.
+func (h *WebhookHandler) retention() time.Duration {
+	if h.Retention <= 0 {
+		return defaultWebhookRetention
+	}
+	return h.Retention
+}
+
+// checkReplay returns an error if an event cannot be protected against replay: it has no UID,
+// or its timestamp is too far from now for a redelivery to be recognised.
+func (h *WebhookHandler) checkReplay(e *WebHookEvent) error {
+	if e.WebhookEventUID == "" {
+		return errors.New("webhook has no event UID")
+	}
+
+	skew := h.MaxSkew
+	if skew <= 0 || skew > h.retention() {
+		skew = h.retention()
+	}
+	d := time.Since(e.EventTimestamp)
+	if d > skew || d < -skew {
+		return errors.Errorf("webhook %s timestamp %s is outside the allowed skew", e.WebhookEventUID, e.EventTimestamp.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// begin records that an event is being handled, returning false if it already is.
+func (h *WebhookHandler) begin(uid string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.inFlight[uid] {
+		return false
+	}
+	h.inFlight[uid] = true
+	return true
+}
+
+// end records that an event is no longer being handled.
+func (h *WebhookHandler) end(uid string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.inFlight, uid)
+}
+
+// mark records a handled event as seen. The event is remembered for the retention window after
+// its timestamp, which covers every redelivery that passes the skew check. The delivery is still
+// acknowledged if marking fails, because asking Starling to send it again would handle it twice.
+func (h *WebhookHandler) mark(r *http.Request, e *WebHookEvent) {
+	if h.Seen == nil {
+		return
+	}
+	if err := h.Seen.Mark(e.WebhookEventUID, e.EventTimestamp.Add(h.retention())); err != nil && h.OnError != nil {
+		h.OnError(r, errors.Wrap(err, "unable to mark webhook as seen"))
+	}
+}
+
+// fail answers a delivery that was not handled with the given status.
+func (h *WebhookHandler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
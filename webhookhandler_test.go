@@ -0,0 +1,320 @@
+package starling
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// webhookKey generates a key pair, returning the private key and the base64-encoded public key.
+func webhookKey(t *testing.T) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal("should generate a key", cross, err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal("should marshal the public key", cross, err)
+	}
+	return key, base64.StdEncoding.EncodeToString(der)
+}
+
+// webhookRequest returns a webhook delivery of body signed with key.
+func webhookRequest(t *testing.T, key *rsa.PrivateKey, body string) *http.Request {
+	digest := sha512.Sum512([]byte(body))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA512, digest[:])
+	if err != nil {
+		t.Fatal("should sign the body", cross, err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	r.Header.Set("X-Hook-Signature", base64.StdEncoding.EncodeToString(sig))
+	return r
+}
+
+func TestWebhookHandlerDispatch(t *testing.T) {
+	key, pub := webhookKey(t)
+	h, err := NewWebhookHandler(pub)
+	checkNoError(t, err)
+
+	var got []string
+	h.OnFeedItem(func(ctx context.Context, e *WebHookEvent, item *WebHookFeedItem) error {
+		got = append(got, "feed item "+item.FeedItemUID+" "+item.AccountUID)
+		return nil
+	})
+	h.OnStandingOrder(func(ctx context.Context, e *WebHookEvent, so *WebHookStandingOrder) error {
+		got = append(got, "standing order "+so.PaymentOrderUID)
+		return nil
+	})
+	h.OnPayment(func(ctx context.Context, e *WebHookEvent, p *WebHookPayment) error {
+		got = append(got, "payment "+p.PaymentUID+" "+string(p.StatusDetails.Status))
+		return nil
+	})
+	h.OnCardStatus(func(ctx context.Context, e *WebHookEvent, cs *WebHookCardStatus) error {
+		got = append(got, "card "+cs.CardUID)
+		return nil
+	})
+	h.OnSavingsGoal(func(ctx context.Context, e *WebHookEvent, sg *WebHookSavingsGoal) error {
+		got = append(got, "savings goal "+sg.SavingsGoalUID)
+		return nil
+	})
+	h.OnPayee(func(ctx context.Context, e *WebHookEvent, p *WebHookPayee) error {
+		got = append(got, "payee "+p.UID)
+		return nil
+	})
+	h.OnOther(func(ctx context.Context, e *WebHookEvent) error {
+		got = append(got, "other "+string(e.Type))
+		return nil
+	})
+
+	cases := []struct {
+		body string
+		want string
+	}{
+		{`{"webhookEventUid":"e1","content":{"feedItemUid":"f1","accountUid":"a1","amount":{"currency":"GBP","minorUnits":100}}}`, "feed item f1 a1"},
+		{`{"webhookEventUid":"e2","content":{"paymentOrderUid":"so1","standingOrderRecurrence":{"frequency":"MONTHLY"}}}`, "standing order so1"},
+		{`{"webhookEventUid":"e3","content":{"paymentUid":"p1","paymentOrderUid":"po1","paymentStatusDetails":{"paymentStatus":"ACCEPTED"}}}`, "payment p1 ACCEPTED"},
+		{`{"webhookEventUid":"e4","content":{"cardUid":"c1","enabled":false}}`, "card c1"},
+		{`{"webhookEventUid":"e5","content":{"savingsGoalUid":"g1","name":"Holiday"}}`, "savings goal g1"},
+		{`{"webhookEventUid":"e6","content":{"payeeUid":"py1","payeeName":"Jane"}}`, "payee py1"},
+		{`{"webhookEventUid":"e7","webhookType":"CARD_STATUS","content":{"cardUid":"c2"}}`, "card c2"},
+		{`{"webhookEventUid":"e8","webhookType":"ACCOUNT_CLOSED","content":{}}`, "other ACCOUNT_CLOSED"},
+	}
+
+	for _, tc := range cases {
+		got = nil
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, webhookRequest(t, key, tc.body))
+
+		checkStatus(t, w.Result(), http.StatusOK)
+		if len(got) != 1 || got[0] != tc.want {
+			t.Error("should dispatch to the callback for the event", cross, got)
+		}
+	}
+}
+
+func TestWebhookHandlerStatus(t *testing.T) {
+	key, pub := webhookKey(t)
+	other, _ := webhookKey(t)
+	h, err := NewWebhookHandler(pub)
+	checkNoError(t, err)
+
+	var errs []error
+	h.OnError = func(r *http.Request, err error) { errs = append(errs, err) }
+	h.OnFeedItem(func(ctx context.Context, e *WebHookEvent, item *WebHookFeedItem) error {
+		if item.FeedItemUID == "fail" {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	unsigned := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{}`))
+	get := webhookRequest(t, key, `{}`)
+	get.Method = http.MethodGet
+
+	cases := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"acknowledged", webhookRequest(t, key, `{"content":{"feedItemUid":"ok"}}`), http.StatusOK},
+		{"no callback", webhookRequest(t, key, `{"content":{"cardUid":"c1"}}`), http.StatusOK},
+		{"callback failed", webhookRequest(t, key, `{"content":{"feedItemUid":"fail"}}`), http.StatusInternalServerError},
+		{"malformed payload", webhookRequest(t, key, `{"content":`), http.StatusBadRequest},
+		{"malformed content", webhookRequest(t, key, `{"webhookType":"FEED_ITEM","content":{"feedItemUid":1}}`), http.StatusBadRequest},
+		{"unsigned", unsigned, http.StatusUnauthorized},
+		{"wrong key", webhookRequest(t, other, `{"content":{"feedItemUid":"ok"}}`), http.StatusUnauthorized},
+		{"wrong method", get, http.StatusMethodNotAllowed},
+		{"too large", webhookRequest(t, key, `{"content":"`+strings.Repeat("x", maxWebhookBody)+`"}`), http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(st *testing.T) {
+			errs = nil
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tc.req)
+
+			checkStatus(st, w.Result(), tc.status)
+			if (tc.status != http.StatusOK) != (len(errs) == 1) {
+				st.Error("should report deliveries that are not acknowledged", cross, errs)
+			}
+		})
+	}
+}
+
+func TestNewWebhookHandlerInvalidKey(t *testing.T) {
+	_, err := NewWebhookHandler("[invalid]publicKey")
+	checkHasError(t, err)
+
+	_, err = NewWebhookHandler(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 16)))
+	checkHasError(t, err)
+}
+
+func TestWebhookHandlerReplay(t *testing.T) {
+	key, pub := webhookKey(t)
+	h, err := NewWebhookHandler(pub)
+	checkNoError(t, err)
+
+	now := time.Now().UTC()
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+	h.Seen = NewMemorySeenStore(100)
+	h.Retention = time.Hour
+	h.MaxSkew = 5 * time.Minute
+
+	var (
+		calls   int
+		fail    bool
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	h.OnFeedItem(func(ctx context.Context, e *WebHookEvent, item *WebHookFeedItem) error {
+		calls++
+		if item.FeedItemUID == "slow" {
+			close(started)
+			<-release
+		}
+		if fail {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	deliver := func(body string) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, webhookRequest(t, key, body))
+		return w.Result().StatusCode
+	}
+	event := func(uid, ts, item string) string {
+		return `{"webhookEventUid":"` + uid + `","eventTimestamp":"` + ts + `","content":{"feedItemUid":"` + item + `"}}`
+	}
+
+	fail = true
+	if status := deliver(event("e1", at(-time.Minute), "f1")); status != http.StatusInternalServerError {
+		t.Error("should fail when the callback fails", cross, status)
+	}
+	fail = false
+	if status := deliver(event("e1", at(-time.Minute), "f1")); status != http.StatusOK {
+		t.Error("should handle a redelivery of a failed event", cross, status)
+	}
+	if status := deliver(event("e1", at(-time.Minute), "f1")); status != http.StatusOK || calls != 2 {
+		t.Error("should acknowledge a duplicate without handling it", cross, status, calls)
+	}
+
+	for _, body := range []string{
+		event("e2", at(-10*time.Minute), "f2"),
+		event("e3", at(10*time.Minute), "f3"),
+		event("", at(0), "f4"),
+	} {
+		if status := deliver(body); status != http.StatusBadRequest {
+			t.Error("should reject events that cannot be protected against replay", cross, status)
+		}
+	}
+	if calls != 2 {
+		t.Error("should not handle rejected events", cross, calls)
+	}
+
+	done := make(chan int)
+	go func() { done <- deliver(event("e5", at(0), "slow")) }()
+	<-started
+	if status := deliver(event("e5", at(0), "slow")); status != http.StatusConflict {
+		t.Error("should ask for a redelivery of an event being handled", cross, status)
+	}
+	close(release)
+	if status := <-done; status != http.StatusOK {
+		t.Error("should handle the first delivery", cross, status)
+	}
+}
+
+// gatedSeenStore pauses when the first delivery marks an event and when a later delivery checks
+// whether it was seen, so that tests can interleave deliveries.
+type gatedSeenStore struct {
+	*MemorySeenStore
+
+	checks    int32
+	marking   chan struct{} // Receives when Mark is called
+	markGate  chan struct{} // Closed to let Mark continue
+	checking  chan struct{} // Receives when a second Seen is called
+	checkGate chan struct{} // Closed to let the second Seen continue
+}
+
+func (s *gatedSeenStore) Seen(uid string) (bool, error) {
+	seen, err := s.MemorySeenStore.Seen(uid)
+	if atomic.AddInt32(&s.checks, 1) > 1 {
+		s.checking <- struct{}{}
+		<-s.checkGate
+	}
+	return seen, err
+}
+
+func (s *gatedSeenStore) Mark(uid string, expires time.Time) error {
+	s.marking <- struct{}{}
+	<-s.markGate
+	return s.MemorySeenStore.Mark(uid, expires)
+}
+
+func TestWebhookHandlerRedeliveryBeforeMark(t *testing.T) {
+	key, pub := webhookKey(t)
+	h, err := NewWebhookHandler(pub)
+	checkNoError(t, err)
+
+	store := &gatedSeenStore{
+		MemorySeenStore: NewMemorySeenStore(10),
+		marking:         make(chan struct{}, 2),
+		markGate:        make(chan struct{}),
+		checking:        make(chan struct{}, 1),
+		checkGate:       make(chan struct{}),
+	}
+	h.Seen = store
+
+	var calls int32
+	h.OnFeedItem(func(ctx context.Context, e *WebHookEvent, item *WebHookFeedItem) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	body := `{"webhookEventUid":"e1","eventTimestamp":"` + time.Now().UTC().Format(time.RFC3339) + `","content":{"feedItemUid":"f1"}}`
+	deliver := func(done chan<- int) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, webhookRequest(t, key, body))
+		done <- w.Result().StatusCode
+	}
+
+	done1, done2 := make(chan int, 1), make(chan int, 1)
+	go deliver(done1)
+	<-store.marking // The callback has run but the event is not yet marked
+
+	go deliver(done2)
+	status := 0
+	select {
+	case status = <-done2:
+	case <-store.checking: // The redelivery found the event unmarked and may now wait for the first
+	}
+
+	close(store.markGate)
+	if first := <-done1; first != http.StatusOK {
+		t.Error("should handle the first delivery", cross, first)
+	}
+	close(store.checkGate)
+	if status == 0 {
+		status = <-done2
+	}
+	if status != http.StatusConflict {
+		t.Error("should ask for a redelivery of an event being handled", cross, status)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Error("should not handle a redelivery that arrives before the event is marked", cross, n)
+	}
+}
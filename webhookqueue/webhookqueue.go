@@ -0,0 +1,455 @@
+/*
+Package webhookqueue stores verified Starling webhooks on disk and dispatches
+them to handlers, retrying those that fail.
+
+Set a Queue on a starling.WebhookHandler so that deliveries are acknowledged
+to Starling as soon as they are safely stored, then run the queue to call the
+handler's callbacks:
+
+	h, _ := starling.NewWebhookHandler(publicKey)
+	h.OnFeedItem(saveFeedItem)
+
+	q, err := webhookqueue.Open("webhooks", h, webhookqueue.Options{Workers: 4})
+	if err != nil {
+		return err
+	}
+	h.Queue = q
+	go q.Run(ctx)
+
+	http.Handle("/webhooks/starling", h)
+
+Events whose callback fails are retried with exponential backoff. Once
+MaxAttempts is reached, or if the event cannot be decoded, it is moved to the
+dead letter directory, from which it can be sent again with Replay.
+
+Each event is stored in its own file, so events queued before a restart are
+dispatched when the queue is next opened. An event being dispatched when the
+process stops is dispatched again, so callbacks must tolerate duplicates. A
+directory must only be opened by one Queue at a time.
+*/
+package webhookqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astravexton/starling"
+	"github.com/astravexton/starling/internal/atomicfile"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Defaults for Options fields that are not set.
+const (
+	defaultMaxAttempts  = 8
+	defaultMinBackoff   = time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+	defaultPollInterval = time.Second
+)
+
+// Names of the directories holding queued and dead-lettered events.
+const (
+	pendingDir = "pending"
+	deadDir    = "dead"
+)
+
+// Dispatcher runs the callbacks for an event. It is implemented by *starling.WebhookHandler.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, e *starling.WebHookEvent) error
+}
+
+// Options configures a Queue.
+type Options struct {
+	Workers      int           // Number of events dispatched at once; defaults to 1
+	MaxAttempts  int           // Attempts before an event is dead-lettered; defaults to 8
+	MinBackoff   time.Duration // Delay before the first retry; defaults to 1 second
+	MaxBackoff   time.Duration // Upper bound on the delay between attempts; defaults to 10 minutes
+	PollInterval time.Duration // Longest time an idle worker waits before checking for events; defaults to 1 second
+
+	// OnError, if set, is called each time dispatching an event fails. Dead is true if the event
+	// has been moved to the dead letter directory.
+	OnError func(e *starling.WebHookEvent, attempts int, dead bool, err error)
+}
+
+// Entry is an event held by the queue
+type Entry struct {
+	Event       *starling.WebHookEvent `json:"event"`
+	EnqueuedAt  time.Time              `json:"enqueuedAt"`
+	Attempts    int                    `json:"attempts"`
+	NextAttempt time.Time              `json:"nextAttempt"`
+	LastError   string                 `json:"lastError,omitempty"`
+}
+
+// pending is an entry waiting to be dispatched.
+type pending struct {
+	Entry
+	name    string // File name within the pending directory
+	claimed bool   // Being dispatched by a worker
+}
+
+// Queue is a durable queue of webhook events. It is safe for concurrent use.
+type Queue struct {
+	dir  string
+	d    Dispatcher
+	opts Options
+
+	mu      sync.Mutex
+	pending map[string]*pending // Keyed by file name
+	wake    chan struct{}
+}
+
+// Open returns a Queue that stores events in dir, creating it if necessary, and dispatches them
+// to d. Events left in dir by a previous Queue are loaded so that they are dispatched by Run.
+func Open(dir string, d Dispatcher, opts Options) (*Queue, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultMinBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultMaxBackoff
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+
+	for _, sub := range []string{pendingDir, deadDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, errors.Wrap(err, "unable to create queue directory")
+		}
+	}
+	if err := atomicfile.SyncDir(dir); err != nil {
+		return nil, errors.Wrap(err, "unable to create queue directory")
+	}
+
+	q := &Queue{
+		dir:     dir,
+		d:       d,
+		opts:    opts,
+		pending: make(map[string]*pending),
+		wake:    make(chan struct{}, 1),
+	}
+
+	entries, err := readEntries(filepath.Join(dir, pendingDir))
+	if err != nil {
+		return nil, err
+	}
+	for name, e := range entries {
+		q.pending[name] = &pending{Entry: e, name: name}
+	}
+	return q, nil
+}
+
+// Enqueue stores an event, returning once it has been written to disk.
+func (q *Queue) Enqueue(e *starling.WebHookEvent) error {
+	now := time.Now()
+	p := &pending{
+		Entry: Entry{Event: e, EnqueuedAt: now, NextAttempt: now},
+		name:  fmt.Sprintf("%020d-%s.json", now.UnixNano(), uuid.New().String()),
+	}
+	if err := writeEntry(filepath.Join(q.dir, pendingDir, p.name), p.Entry); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	q.pending[p.name] = p
+	q.mu.Unlock()
+	q.notify()
+	return nil
+}
+
+// Len returns the number of events waiting to be dispatched, including those being dispatched.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Run dispatches events using the configured number of workers, returning once ctx is done and
+// the workers have stopped. Events being dispatched when ctx is done are left in the queue without
+// counting the attempt.
+func (q *Queue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < q.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work dispatches events until ctx is done.
+func (q *Queue) work(ctx context.Context) {
+	for {
+		p, wait := q.claim(time.Now())
+		if p == nil {
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-q.wake:
+			case <-t.C:
+			}
+			t.Stop()
+			continue
+		}
+
+		err := q.d.Dispatch(ctx, p.Event)
+		if err != nil && ctx.Err() != nil {
+			q.release(p)
+			return
+		}
+		q.complete(p, err)
+	}
+}
+
+// claim returns the oldest entry that is due, marking it as claimed. If there is none it returns
+// how long to wait before looking again.
+func (q *Queue) claim(now time.Time) (*pending, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var (
+		next *pending
+		wait = q.opts.PollInterval
+	)
+	for _, p := range q.pending {
+		if p.claimed {
+			continue
+		}
+		if d := p.NextAttempt.Sub(now); d > 0 {
+			if d < wait {
+				wait = d
+			}
+			continue
+		}
+		if next == nil || p.name < next.name {
+			next = p
+		}
+	}
+	if next == nil {
+		return nil, wait
+	}
+	next.claimed = true
+	return next, 0
+}
+
+// release returns an entry to the queue without recording an attempt.
+func (q *Queue) release(p *pending) {
+	q.mu.Lock()
+	p.claimed = false
+	q.mu.Unlock()
+	q.notify()
+}
+
+// complete records the outcome of dispatching an entry: removing it on success, scheduling a
+// retry, or moving it to the dead letter directory.
+func (q *Queue) complete(p *pending, err error) {
+	path := filepath.Join(q.dir, pendingDir, p.name)
+	if err == nil {
+		q.remove(p)
+		if rmErr := removeEntry(path); rmErr != nil && q.opts.OnError != nil {
+			q.opts.OnError(p.Event, p.Attempts+1, false, errors.Wrap(rmErr, "unable to remove dispatched webhook"))
+		}
+		return
+	}
+
+	e := p.Entry
+	e.Attempts++
+	e.LastError = err.Error()
+	dead := e.Attempts >= q.opts.MaxAttempts || errors.Is(err, starling.ErrMalformedWebhook)
+	if q.opts.OnError != nil {
+		q.opts.OnError(e.Event, e.Attempts, dead, err)
+	}
+
+	if dead {
+		if wErr := writeEntry(filepath.Join(q.dir, deadDir, p.name), e); wErr != nil {
+			q.retry(p, e, wErr)
+			return
+		}
+		q.remove(p)
+		if rmErr := removeEntry(path); rmErr != nil && q.opts.OnError != nil {
+			q.opts.OnError(e.Event, e.Attempts, true, errors.Wrap(rmErr, "unable to remove dead-lettered webhook"))
+		}
+		return
+	}
+
+	e.NextAttempt = time.Now().Add(q.backoff(e.Attempts))
+	if wErr := writeEntry(path, e); wErr != nil && q.opts.OnError != nil {
+		q.opts.OnError(e.Event, e.Attempts, false, wErr)
+	}
+	q.retry(p, e, nil)
+}
+
+// retry returns an entry to the queue to be dispatched again. If it could not be dead-lettered
+// because of err, it is retried after the longest backoff.
+func (q *Queue) retry(p *pending, e Entry, err error) {
+	if err != nil {
+		e.NextAttempt = time.Now().Add(q.opts.MaxBackoff)
+		if q.opts.OnError != nil {
+			q.opts.OnError(e.Event, e.Attempts, false, errors.Wrap(err, "unable to dead-letter webhook"))
+		}
+	}
+
+	q.mu.Lock()
+	p.Entry = e
+	p.claimed = false
+	q.mu.Unlock()
+	q.notify()
+}
+
+// remove forgets an entry.
+func (q *Queue) remove(p *pending) {
+	q.mu.Lock()
+	delete(q.pending, p.name)
+	q.mu.Unlock()
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := float64(q.opts.MinBackoff) * math.Pow(2, float64(attempts-1))
+	if d > float64(q.opts.MaxBackoff) {
+		return q.opts.MaxBackoff
+	}
+	return time.Duration(d)
+}
+
+// notify wakes an idle worker.
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// DeadLetters returns the events that could not be dispatched, oldest first.
+func (q *Queue) DeadLetters() ([]Entry, error) {
+	entries, err := readEntries(filepath.Join(q.dir, deadDir))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dead := make([]Entry, len(names))
+	for i, name := range names {
+		dead[i] = entries[name]
+	}
+	return dead, nil
+}
+
+// Replay moves the dead-lettered event with the given WebhookEventUID back to the queue with its
+// attempts reset. An error is returned if there is no such event.
+func (q *Queue) Replay(uid string) error {
+	n, err := q.replay(func(e Entry) bool { return e.Event != nil && e.Event.WebhookEventUID == uid })
+	if err == nil && n == 0 {
+		err = errors.Errorf("no dead letter for webhook %s", uid)
+	}
+	return err
+}
+
+// ReplayAll moves every dead-lettered event back to the queue with its attempts reset, returning
+// the number of events replayed.
+func (q *Queue) ReplayAll() (int, error) {
+	return q.replay(func(Entry) bool { return true })
+}
+
+// replay moves the dead-lettered events matching fn back to the queue.
+func (q *Queue) replay(fn func(Entry) bool) (int, error) {
+	entries, err := readEntries(filepath.Join(q.dir, deadDir))
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for name, e := range entries {
+		if !fn(e) {
+			continue
+		}
+
+		e.Attempts = 0
+		e.LastError = ""
+		e.NextAttempt = time.Now()
+		if err := writeEntry(filepath.Join(q.dir, pendingDir, name), e); err != nil {
+			return n, err
+		}
+		if err := removeEntry(filepath.Join(q.dir, deadDir, name)); err != nil {
+			return n, errors.Wrap(err, "unable to remove dead letter")
+		}
+
+		q.mu.Lock()
+		q.pending[name] = &pending{Entry: e, name: name}
+		q.mu.Unlock()
+		n++
+	}
+	if n > 0 {
+		q.notify()
+	}
+	return n, nil
+}
+
+// writeEntry writes an entry to path, returning once the file and its directory entry have been
+// flushed to disk.
+func writeEntry(path string, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode webhook")
+	}
+	return errors.Wrap(atomicfile.WriteFile(path, b), "unable to save webhook")
+}
+
+// removeEntry removes the entry at path, if it exists, and flushes its directory to disk.
+func removeEntry(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return atomicfile.SyncDir(filepath.Dir(path))
+}
+
+// readEntries reads the entries in dir, keyed by file name. Temporary files left by an
+// interrupted write are ignored.
+func readEntries(dir string) (map[string]Entry, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read queue directory")
+	}
+
+	entries := make(map[string]Entry)
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), ".json") {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read webhook")
+		}
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse webhook %s", fi.Name())
+		}
+		entries[fi.Name()] = e
+	}
+	return entries, nil
+}
@@ -0,0 +1,273 @@
+package webhookqueue
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/astravexton/starling"
+	"github.com/astravexton/starling/starlingtest"
+	"github.com/pkg/errors"
+)
+
+const cross = "✗"
+
+// dispatcher records dispatched events, failing each event a set number of times.
+type dispatcher struct {
+	mu    sync.Mutex
+	fails map[string]int
+	err   error
+	calls map[string]int
+}
+
+func (d *dispatcher) Dispatch(ctx context.Context, e *starling.WebHookEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.calls[e.WebhookEventUID]++
+	if d.fails[e.WebhookEventUID] > 0 {
+		d.fails[e.WebhookEventUID]--
+		return d.err
+	}
+	return nil
+}
+
+func (d *dispatcher) count(uid string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.calls[uid]
+}
+
+func newDispatcher(fails map[string]int) *dispatcher {
+	return &dispatcher{fails: fails, err: errors.New("database unavailable"), calls: map[string]int{}}
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "webhookqueue")
+	if err != nil {
+		t.Fatal("should create a temporary directory", cross, err)
+	}
+	return dir
+}
+
+// waitFor fails the test if cond is not met within a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("should", what, cross)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func run(q *Queue) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestQueueRetries(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	d := newDispatcher(map[string]int{"e1": 2})
+	var (
+		mu       sync.Mutex
+		attempts []int
+	)
+	q, err := Open(dir, d, Options{
+		Workers:    3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+		OnError: func(e *starling.WebHookEvent, n int, dead bool, err error) {
+			mu.Lock()
+			attempts = append(attempts, n)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal("should open the queue", cross, err)
+	}
+	defer run(q)()
+
+	for _, uid := range []string{"e1", "e2", "e3"} {
+		if err := q.Enqueue(&starling.WebHookEvent{WebhookEventUID: uid}); err != nil {
+			t.Fatal("should enqueue the event", cross, err)
+		}
+	}
+
+	waitFor(t, "dispatch every event", func() bool { return q.Len() == 0 })
+	if d.count("e1") != 3 || d.count("e2") != 1 || d.count("e3") != 1 {
+		t.Error("should retry failed events until they succeed", cross, d.calls)
+	}
+	mu.Lock()
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Error("should report each failed attempt", cross, attempts)
+	}
+	mu.Unlock()
+
+	files, _ := ioutil.ReadDir(dir + "/pending")
+	if len(files) != 0 {
+		t.Error("should remove dispatched events from disk", cross, len(files))
+	}
+}
+
+func TestQueueDeadLetters(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	d := newDispatcher(map[string]int{"e1": 3, "e2": 3})
+	q, err := Open(dir, d, Options{MaxAttempts: 2, MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatal("should open the queue", cross, err)
+	}
+	stop := run(q)
+
+	q.Enqueue(&starling.WebHookEvent{WebhookEventUID: "e1"})
+	q.Enqueue(&starling.WebHookEvent{WebhookEventUID: "e2"})
+	waitFor(t, "dead-letter failing events", func() bool { return q.Len() == 0 })
+
+	dead, err := q.DeadLetters()
+	if err != nil || len(dead) != 2 || dead[0].Event.WebhookEventUID != "e1" || dead[0].Attempts != 2 || dead[0].LastError != "database unavailable" {
+		t.Fatal("should list dead letters", cross, dead, err)
+	}
+	if d.count("e1") != 2 {
+		t.Error("should stop after MaxAttempts", cross, d.count("e1"))
+	}
+
+	if err := q.Replay("e1"); err != nil {
+		t.Fatal("should replay the dead letter", cross, err)
+	}
+	waitFor(t, "dispatch the replayed event", func() bool { return d.count("e1") >= 3 && q.Len() == 0 })
+	if err := q.Replay("e1"); err == nil {
+		t.Error("should fail to replay an event that is not dead", cross)
+	}
+	stop()
+
+	d.fails["e2"] = 0
+	if n, err := q.ReplayAll(); err != nil || n != 1 {
+		t.Error("should replay every dead letter", cross, n, err)
+	}
+	if dead, _ := q.DeadLetters(); len(dead) != 0 || q.Len() != 1 {
+		t.Error("should move replayed events to the queue", cross, len(dead), q.Len())
+	}
+}
+
+func TestQueueMalformed(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	h := starling.NewWebhookHandlerWithVerifier(starling.NewVerifier())
+	h.OnFeedItem(func(ctx context.Context, e *starling.WebHookEvent, item *starling.WebHookFeedItem) error {
+		return nil
+	})
+	q, err := Open(dir, h, Options{MaxAttempts: 5})
+	if err != nil {
+		t.Fatal("should open the queue", cross, err)
+	}
+	defer run(q)()
+
+	q.Enqueue(&starling.WebHookEvent{WebhookEventUID: "e1", Type: starling.WebhookTypeFeedItem, Content: []byte(`{"feedItemUid":1}`)})
+	waitFor(t, "dead-letter malformed events", func() bool { return q.Len() == 0 })
+
+	if dead, _ := q.DeadLetters(); len(dead) != 1 || dead[0].Attempts != 1 {
+		t.Error("should dead-letter malformed events without retrying", cross, dead)
+	}
+}
+
+func TestQueueRestart(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := Open(dir, newDispatcher(nil), Options{})
+	if err != nil {
+		t.Fatal("should open the queue", cross, err)
+	}
+	q.Enqueue(&starling.WebHookEvent{WebhookEventUID: "e1"})
+	q.Enqueue(&starling.WebHookEvent{WebhookEventUID: "e2"})
+
+	d := newDispatcher(nil)
+	q, err = Open(dir, d, Options{})
+	if err != nil || q.Len() != 2 {
+		t.Fatal("should load queued events", cross, q.Len(), err)
+	}
+	defer run(q)()
+
+	waitFor(t, "dispatch events queued before the restart", func() bool { return q.Len() == 0 })
+	if d.count("e1") != 1 || d.count("e2") != 1 {
+		t.Error("should dispatch each event once", cross, d.calls)
+	}
+}
+
+func TestQueueWebhookHandler(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	signer, err := starlingtest.NewWebhookSigner()
+	if err != nil {
+		t.Fatal("should create a signer", cross, err)
+	}
+	h, err := starling.NewWebhookHandler(signer.PublicKey())
+	if err != nil {
+		t.Fatal("should create a handler", cross, err)
+	}
+	h.Seen = starling.NewMemorySeenStore(10)
+
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	h.OnFeedItem(func(ctx context.Context, e *starling.WebHookEvent, item *starling.WebHookFeedItem) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	q, err := Open(dir, h, Options{MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatal("should open the queue", cross, err)
+	}
+	h.Queue = q
+
+	payload := starlingtest.CardPurchase(starlingtest.WebhookItem{Amount: 450})
+	for i := 0; i < 2; i++ {
+		req, _ := signer.Request("/webhooks", payload)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Error("should acknowledge the delivery", cross, w.Code)
+		}
+	}
+	if q.Len() != 1 {
+		t.Error("should queue a redelivered event once", cross, q.Len())
+	}
+
+	defer run(q)()
+	waitFor(t, "dispatch the queued event", func() bool { return q.Len() == 0 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 {
+		t.Error("should retry the callback", cross, calls)
+	}
+}